@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"html/template"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
 
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -59,11 +62,67 @@ func (es FieldErrors) As(err interface{}) bool {
 
 // Error implements errors.Error
 func (es FieldErrors) Error() string {
-	bs, err := json.Marshal(es)
-	if err != nil {
+	// the messages are meant to be read, so characters like < and & aren't escaped
+	var bs bytes.Buffer
+	enc := json.NewEncoder(&bs)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(es); err != nil {
 		return err.Error()
 	}
-	return string(bs)
+	return strings.TrimSuffix(bs.String(), "\n")
+}
+
+// JSONObject serializes the errors as a json object keyed by the path of each error, e.g. `{"user.email":"'email' must be a valid email address"}`.
+// When more than one error shares a path, the value is an array of their messages.
+func (es FieldErrors) JSONObject() ([]byte, error) {
+	object := make(map[string]interface{}, len(es))
+	for _, err := range es {
+		var path string
+		if fe, ok := err.(*FieldError); ok {
+			path = fe.Path
+		}
+		switch messages := object[path].(type) {
+		case nil:
+			object[path] = err.Error()
+		case string:
+			object[path] = []string{messages, err.Error()}
+		case []string:
+			object[path] = append(messages, err.Error())
+		}
+	}
+	return json.Marshal(object)
+}
+
+// FirstByPath returns the first error of each path, e.g. to show a single message next to each field of a form
+func (es FieldErrors) FirstByPath() map[string]error {
+	first := make(map[string]error, len(es))
+	for _, err := range es {
+		var path string
+		if fe, ok := err.(*FieldError); ok {
+			path = fe.Path
+		}
+		if _, ok := first[path]; !ok {
+			first[path] = err
+		}
+	}
+	return first
+}
+
+// Sort sorts the errors by their path and then by their message, so that their order doesn't depend on the order
+// that the keys of maps are validated in
+func (es FieldErrors) Sort() {
+	path := func(err error) string {
+		if fe, ok := err.(*FieldError); ok {
+			return fe.Path
+		}
+		return ""
+	}
+	sort.SliceStable(es, func(i, j int) bool {
+		if pi, pj := path(es[i]), path(es[j]); pi != pj {
+			return pi < pj
+		}
+		return es[i].Error() < es[j].Error()
+	})
 }
 
 // Errors implements Errors
@@ -76,15 +135,42 @@ func (es *FieldErrors) Add(errs ...error) {
 	for _, err := range errs {
 		if errs, ok := err.(Errors); ok {
 			es.Add(errs.Errors()...)
+		} else {
+			*es = append(*es, err)
+		}
+	}
+}
+
+// addField adds the errors returned by the rules of the field at the path passed in, making sure each of them is a *FieldError
+func (es *FieldErrors) addField(path string, err error) {
+	if errs, ok := err.(Errors); ok {
+		for _, err := range errs.Errors() {
+			es.addField(path, err)
 		}
-		*es = append(*es, err)
+		return
+	}
+	if _, ok := err.(*FieldError); !ok {
+		err = NewFieldError(path, err)
 	}
+	*es = append(*es, err)
 }
 
 // FieldError is the error returned when a field rule returns an error
 type FieldError struct {
 	Path    string `json:"path,omitempty"`
 	Message error  `json:"message,omitempty"`
+
+	// formatter renders the message, if it is set by `Config.ErrorFormatter`
+	formatter func(fe *FieldError) string
+}
+
+// NewFieldError returns the error of the field at the path passed in, e.g. `items[0].name`. Rules can return it to report
+// errors on a path other than their field's
+func NewFieldError(path string, err error) *FieldError {
+	return &FieldError{
+		Path:    path,
+		Message: err,
+	}
 }
 
 // Is implements errors.Is
@@ -107,24 +193,91 @@ func (fe *FieldError) As(i interface{}) bool {
 
 // Error implements errors.Error
 func (fe *FieldError) Error() string {
+	if fe.formatter != nil {
+		return fe.formatter(fe)
+	}
 	return fe.Message.Error()
 }
 
 // MarshalJSON implements the json.Marshaler interface
 func (fe *FieldError) MarshalJSON() ([]byte, error) {
-	// TODO: after we have a clean `Path` for each error,
-	//       add a config boolean that renders these as json objects instead
-	return []byte(fmt.Sprintf("\"%s\"", fe.Message)), nil
+	// characters like < and & are left unescaped for `FieldErrors.Error`. json.Marshal still escapes them
+	var bs bytes.Buffer
+	enc := json.NewEncoder(&bs)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(fe.Error()); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(bs.Bytes(), []byte("\n")), nil
+}
+
+// ParseError is the error returned when a rule expression can not be parsed. It holds the position of the token
+// that could not be parsed, so that tools can highlight it
+type ParseError struct {
+	// Offset is the 0 based byte offset of the token in the expression
+	Offset int
+
+	// Line and Column are the 1 based line and column of the token in the expression
+	Line, Column int
+
+	// Token is the token that could not be parsed, e.g. `:`
+	Token string
+
+	// Expected is the category of token that was expected in its place: "rule", "operator", "param" or "token"
+	// when the expression could not be split into tokens at all
+	Expected string
+
+	err error
+}
+
+// Error implements errors.Error
+func (e *ParseError) Error() string {
+	return e.err.Error()
+}
+
+// typeNameError adds the go type of a field after its name in the message of an error
+type typeNameError struct {
+	error
+	message string
+}
+
+// Error implements errors.Error
+func (e *typeNameError) Error() string {
+	return e.message
+}
+
+// Unwrap implements errors.Unwrap
+func (e *typeNameError) Unwrap() error {
+	return e.error
+}
+
+// withTypeName adds the go type passed in after the first mention of the field name in the message of every error returned by a rule
+func withTypeName(err error, name string, typ reflect.Type) error {
+	switch e := err.(type) {
+	case Errors:
+		var errs FieldErrors
+		for _, err := range e.Errors() {
+			errs = append(errs, withTypeName(err, name, typ))
+		}
+		return errs
+	case *FieldError:
+		return NewFieldError(e.Path, withTypeName(e.Message, name, typ))
+	}
+	quoted := fmt.Sprintf("'%s'", name)
+	return &typeNameError{
+		error:   err,
+		message: strings.Replace(err.Error(), quoted, fmt.Sprintf("%s (%s)", quoted, typ), 1),
+	}
 }
 
 // errorf handles i18n errors
-func errorf(tag language.Tag, str string, is ...interface{}) error {
-	return errors.New(message.NewPrinter(tag).Sprintf(str, is...))
+func errorf(tag language.Tag, key message.Reference, is ...interface{}) error {
+	return errors.New(message.NewPrinter(tag, message.Catalog(messages)).Sprintf(key, is...))
 }
 
 // errorTemplate handles i18n template based errors
-func errorTemplate(tag language.Tag, str string, context interface{}) error {
-	str = message.NewPrinter(tag).Sprint(str)
+func errorTemplate(tag language.Tag, key message.Reference, context interface{}) error {
+	str := message.NewPrinter(tag, message.Catalog(messages)).Sprintf(key)
 	var bs bytes.Buffer
 	if t, err := template.New(str).Funcs(template.FuncMap{
 		"minus": func(a, b int) int {
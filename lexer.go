@@ -2,13 +2,18 @@ package validator
 
 import (
 	"fmt"
+	"regexp"
 	"runtime"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 var eof = rune(-1)
 
+// timeLiteral matches a date, e.g. `2030-01-01`, or an RFC3339 time, e.g. `2030-01-01T00:00:00Z`, that can be written in a tag without quotes
+var timeLiteral = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}))?`)
+
 type lexer struct {
 	buffer     string
 	start      int
@@ -42,14 +47,16 @@ func (l *lexer) Next() *token {
 			return l.emitError(err)
 		}
 		return l.emit(typeEOF)
-	} else if isAnd := l.acceptPrefix("&"); isAnd {
+	} else if isAnd := l.acceptPrefix("&&") || l.acceptPrefix("&"); isAnd {
 		return l.emit(typeAnd)
-	} else if isOr := l.acceptPrefix("|"); isOr {
+	} else if isOr := l.acceptPrefix("||") || l.acceptPrefix("|"); isOr {
 		return l.emit(typeOr)
 	} else if isColon := l.acceptPrefix(":"); isColon {
 		return l.emit(typeColon)
 	} else if isComma := l.acceptPrefix(","); isComma {
 		return l.emit(typeComma)
+	} else if isOptional := l.acceptPrefix("?"); isOptional {
+		return l.emit(typeOptional)
 	} else if isOpenParen := l.acceptPrefix("("); isOpenParen {
 		l.parenStack++
 		return l.emit(typeOpenParen)
@@ -65,6 +72,8 @@ func (l *lexer) Next() *token {
 		return l.emit(typeString)
 	} else if err != nil {
 		return l.emitError(err)
+	} else if isTime := l.acceptTime(); isTime {
+		return l.emit(typeFunction)
 	} else if isNumber := l.acceptNumber(); isNumber {
 		return l.emit(typeNumber)
 	} else if isWhiteSpace := l.acceptSpace(); isWhiteSpace {
@@ -98,17 +107,34 @@ func (l *lexer) Backup() {
 }
 
 func (l *lexer) emitError(err error) *token {
-	return &token{typeError, err.Error()}
+	line, col := l.position(l.start)
+	return &token{typeError, err.Error(), line, col}
 }
 
 func (l *lexer) emit(t tokenType) *token {
 	l.logd("emit(%s) -> l.buffer[%d:%d] = %s\n", t, l.start, l.pos, l.buffer[l.start:l.pos])
+	line, col := l.position(l.start)
 	tkn := token{
-		t, l.buffer[l.start:l.pos],
+		t, l.buffer[l.start:l.pos], line, col,
 	}
 	return &tkn
 }
 
+// position returns the 1 based line and column of an offset in the buffer
+func (l *lexer) position(offset int) (int, int) {
+	if offset > l.len {
+		offset = l.len
+	}
+	line, lineStart := 1, 0
+	for i := 0; i < offset; i++ {
+		if l.buffer[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	return line, utf8.RuneCountInString(l.buffer[lineStart:offset]) + 1
+}
+
 func (l *lexer) hasNext() bool {
 	l.logd("hasNext() -> %d < %d = %t", l.pos, l.len, l.pos < l.len)
 	return l.pos < l.len
@@ -228,7 +254,8 @@ func (l *lexer) acceptNumber() bool {
 	return l.pos != l.start
 }
 
-// acceptString accepts a string started by either a single or double quote
+// acceptString accepts a string started by either a single or double quote. Quotes and backslashes can be
+// escaped inside of the string with a backslash, e.g. `'it\'s'`
 func (l *lexer) acceptString() (bool, error) {
 	var isSingleQuote, isDoubleQuote bool
 	if isSingleQuote = l.accept("'"); !isSingleQuote {
@@ -237,7 +264,9 @@ func (l *lexer) acceptString() (bool, error) {
 		}
 	}
 	for {
-		if isSingleQuote && l.accept("'") {
+		if l.acceptPrefix("\\'") || l.acceptPrefix("\\\"") || l.acceptPrefix("\\\\") {
+			continue
+		} else if isSingleQuote && l.accept("'") {
 			return true, nil
 		} else if isDoubleQuote && l.accept("\"") {
 			return true, nil
@@ -261,9 +290,41 @@ func (l *lexer) acceptSpace() bool {
 	return l.start != l.pos
 }
 
+// acceptTime accepts a date or an RFC3339 time, which would otherwise be split into numbers and colons
+func (l *lexer) acceptTime() bool {
+	literal := timeLiteral.FindString(l.buffer[l.pos:])
+	if len(literal) == 0 {
+		return false
+	}
+	l.pos += len(literal)
+	if l.isAlphaNumeric(l.peak()) {
+		l.pos = l.start
+		return false
+	}
+	return true
+}
+
 func (l *lexer) acceptFunction() bool {
+	// accept a `$` at the start of a reference to a field, e.g. `$Max`, and a `.` at the start of a file extension, e.g. `.jpg`
+	isReference := l.peak() == '$'
+	if r := l.peak(); r == '$' || r == '.' {
+		l.next()
+		if !l.isAlphaNumeric(l.peak()) {
+			l.backup()
+			return false
+		}
+	}
 	for {
-		if r := l.next(); !l.isAlphaNumeric(r) {
+		if r := l.next(); r == '.' && l.pos-1 != l.start && l.isAlphaNumeric(l.peak()) {
+			// accept dots between identifiers, e.g. `root.Field`
+			continue
+		} else if r == '[' && isReference {
+			// accept indexes and map keys in references, e.g. `$Items[0].Price` or `$Prices[usd]`
+			if !l.acceptIndex() {
+				l.backup()
+				break
+			}
+		} else if !l.isAlphaNumeric(r) {
 			if r != eof {
 				l.backup()
 			}
@@ -273,6 +334,44 @@ func (l *lexer) acceptFunction() bool {
 	return l.start != l.pos
 }
 
+// acceptIndex accepts the rest of an index or a map key after its `[`, e.g. `0]` or `usd]`
+func (l *lexer) acceptIndex() bool {
+	start := l.pos
+	for l.isAlphaNumeric(l.peak()) {
+		l.next()
+	}
+	if l.pos == start || l.peak() != ']' {
+		l.pos = start
+		return false
+	}
+	l.next()
+	return true
+}
+
+// offset returns the byte offset in the buffer of a token, which can lag behind the lexer after a lookahead
+func (l *lexer) offset(t *token) int {
+	line, col := 1, 1
+	for i, r := range l.buffer {
+		if line == t.line && col == t.col {
+			return i
+		} else if r == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+	return l.len
+}
+
+// runeAt returns the rune at an offset in the buffer, or an empty string at the end of the buffer
+func (l *lexer) runeAt(offset int) string {
+	if offset >= l.len {
+		return ""
+	}
+	r, _ := utf8.DecodeRuneInString(l.buffer[offset:])
+	return string(r)
+}
+
 // isAlphaNumeric reports whether r is an alphabetic, digit, or underscore.
 func (l *lexer) isAlphaNumeric(r rune) bool {
 	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
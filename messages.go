@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+// messages is the catalog of translated error messages used by the rules
+var messages = catalog.NewBuilder(catalog.Fallback(language.English))
+
+// RegisterMessages registers translations of the default rules' error messages for a language.
+// Messages are keyed by the name of the rule that returns them (e.g. "required" or "email"). Rules with
+// more than one message use the rule name followed by a dot and a qualifier (e.g. "number.min" or "number.digits.max").
+// The messages are format strings that take the same arguments as their english counterparts, except for the rules that
+// list several names or values ("eq", "notin", "xor", "or", "and", "startswith", "endswith" and "ext"). Their messages are text/template
+// templates that range over the list, so that each language can join it together with its own words.
+//
+// Example
+//  validator.RegisterMessages(language.French, map[string]string{
+//    "required": "'%s' est obligatoire",
+//  })
+//
+func RegisterMessages(tag language.Tag, msgs map[string]string) {
+	for key, msg := range msgs {
+		if err := messages.SetString(tag, key, msg); err != nil {
+			panic(fmt.Errorf("bad message for '%s': %s", key, err))
+		}
+	}
+}
+
+func init() {
+	RegisterMessages(language.Spanish, spanish)
+}
+
+// spanish are the spanish translations of the default rules' error messages
+var spanish = map[string]string{
+	"required":               "'%s' es obligatorio",
+	"empty":                  "'%s' debe colocar omitempty antes de las demás etiquetas",
+	"eq":                     `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 1}} debe ser igual a {{else if eq $i $last}} o {{else if gt $i 0}}, {{end}}'{{$field}}'{{end}}`,
+	"xor":                    `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 0}}o bien {{else if eq $i $last}} o {{else}}, {{end}}'{{$field}}'{{end}} debe estar establecido`,
+	"or":                     `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 0}}al menos uno de {{else if eq $i $last}} o {{else}}, {{end}}'{{$field}}'{{end}} debe estar establecido`,
+	"and":                    `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i $last}} y {{else if gt $i 0}}, {{end}}'{{$field}}'{{end}} deben estar establecidos`,
+	"name":                   "'%s' debe ser un nombre válido",
+	"email":                  "'%s' debe ser una dirección de correo electrónico válida",
+	"password.length":        "'%s' debe tener al menos 6 caracteres",
+	"password.special":       "'%s' debe contener al menos un número o carácter especial (p. ej. @!#)",
+	"number":                 "'%s' solo puede contener números",
+	"number.digits":          "'%s' debe tener de %d a %d dígitos",
+	"number.digits.max":      "'%s' debe tener %d dígitos o menos",
+	"number.digits.min":      "'%s' debe tener %d dígitos o más",
+	"number.range":           "'%s' debe ser de %d a %d",
+	"number.max":             "'%s' debe ser %d o menos",
+	"number.min":             "'%s' debe ser %d o más",
+	"letters":                "'%s' solo puede contener letras y espacios",
+	"contrast":               "'%s' debe tener una relación de contraste de al menos %s contra %s",
+	"divisible_by":           "'%s' debe ser un múltiplo de '%s'",
+	"dockertag":              "'%s' debe ser una referencia de imagen válida",
+	"wholeseconds":           "'%s' no debe tener precisión inferior a un segundo",
+	"in":                     "'%s' debe ser uno de los %s permitidos",
+	"in_fold":                "'%s' debe ser uno de los %s permitidos",
+	"distinct":               "'%s' no debe contener duplicados",
+	"degrees":                "'%s' debe estar entre 0 y 360 grados",
+	"radians":                "'%s' debe estar entre 0 y 2π radianes",
+	"enum_for":               "'%s' no es válido para el '%s' seleccionado",
+	"colwidth":               "'%s' debe tener entre %d y %d columnas de ancho",
+	"enum":                   "'%s' debe ser un %s válido",
+	"eqfield":                "'%s' debe coincidir con '%s'",
+	"nefield":                "'%s' debe ser diferente de '%s'",
+	"glob":                   "'%s' no coincide con el patrón requerido",
+	"hex":                    "'%s' debe ser hexadecimal válido",
+	"base64":                 "'%s' debe ser base64 válido",
+	"base32":                 "'%s' debe ser base32 válido",
+	"base58":                 "'%s' debe ser base58 válido",
+	"json":                   "'%s' debe ser json válido",
+	"startswith":             `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $affix := .}}{{if eq $i 0}}'{{$affix}}'{{else}}{{if eq $i 1}} debe empezar con {{else if eq $i $last}} o {{else}}, {{end}}{{$affix}}{{end}}{{end}}`,
+	"endswith":               `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $affix := .}}{{if eq $i 0}}'{{$affix}}'{{else}}{{if eq $i 1}} debe terminar con {{else if eq $i $last}} o {{else}}, {{end}}{{$affix}}{{end}}{{end}}`,
+	"lowercase":              "'%s' debe estar en minúsculas",
+	"uppercase":              "'%s' debe estar en mayúsculas",
+	"notblank":               "'%s' no debe estar en blanco",
+	"strongpassword.length":  "'%s' debe tener al menos %d caracteres",
+	"strongpassword.upper":   "'%s' debe contener %d o más letras mayúsculas",
+	"strongpassword.lower":   "'%s' debe contener %d o más letras minúsculas",
+	"strongpassword.digits":  "'%s' debe contener %d o más dígitos",
+	"strongpassword.symbols": "'%s' debe contener %d o más símbolos (p. ej. @!#)",
+	"latitude":               "'%s' debe ser una latitud válida",
+	"longitude":              "'%s' debe ser una longitud válida",
+	"hexcolor":               "'%s' debe ser un color hexadecimal válido",
+	"currency":               "'%s' debe ser un código de moneda válido",
+	"country":                "'%s' debe ser un código de país ISO válido",
+	"semver":                 "'%s' debe ser una versión semántica válida",
+	"semver.constraint":      "'%s' debe ser una versión %s",
+	"sorted":                 "'%s' debe estar ordenado",
+	"lt":                     "'%s' debe ser menor que %s",
+	"lte":                    "'%s' debe ser %s o menos",
+	"gt":                     "'%s' debe ser mayor que %s",
+	"gte":                    "'%s' debe ser %s o más",
+	"format":                 "'%s' no es un %s válido",
+	"runelen":                "'%s' debe tener de %d a %d caracteres",
+	"runelen.min":            "'%s' debe tener %d caracteres o más",
+	"inlist":                 "'%s' no está permitido",
+	"notin":                  `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 1}}{{if eq $len 2}} no debe ser igual a {{else}} no debe ser ninguno de {{end}}{{else if eq $i $last}} o {{else if gt $i 0}}, {{end}}'{{$field}}'{{end}}`,
+	"normalized":             "'%s' debe estar en forma normalizada",
+	"duration":               "'%s' debe ser una duración válida",
+	"duration.range":         "'%s' debe estar entre %s y %s",
+	"duration.min":           "'%s' debe ser al menos %s",
+	"ext":                    `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $affix := .}}{{if eq $i 0}}'{{$affix}}'{{else}}{{if eq $i 1}} debe tener la extensión {{else if eq $i $last}} o {{else}}, {{end}}{{$affix}}{{end}}{{end}}`,
+	"iban":                   "'%s' debe ser un IBAN válido",
+	"multipleof":             "'%s' debe ser un múltiplo de %s",
+	"before":                 "'%s' debe ser anterior a %s",
+	"before.now":             "'%s' debe estar en el pasado",
+	"after":                  "'%s' debe ser posterior a %s",
+	"after.now":              "'%s' debe estar en el futuro",
+	"bytesize":               "'%s' debe tener de %s a %s bytes",
+	"bytesize.max":           "'%s' debe tener como máximo %s bytes",
+	"mac":                    "'%s' debe ser una dirección MAC válida",
+	"hostname":               "'%s' debe ser un nombre de host válido",
+	"pattern":                "'%s' no tiene un formato válido",
+	"nonempty":               "'%s' no debe estar vacío",
+	"printable":              "'%s' no debe contener caracteres de control",
+	"implements":             "'%s' debe implementar %s",
+	"slug":                   "'%s' debe ser un slug de url válido",
+	"decimal":                "'%s' debe ser un número decimal",
+	"decimal.scale":          "'%s' debe tener como máximo %s decimales",
+	"decimal.precision":      "'%s' debe tener como máximo %s dígitos antes del punto decimal",
+}
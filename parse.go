@@ -5,8 +5,80 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
+	"time"
 )
 
+// NodeType is the type of a node in the parse tree of a rule expression
+type NodeType int
+
+const (
+	// NodeRule is a rule and its params, e.g. `number:1,10`
+	NodeRule = NodeType(iota)
+
+	// NodeAnd is an `&` between two nodes
+	NodeAnd
+
+	// NodeOr is an `|` between two nodes
+	NodeOr
+)
+
+// Node is a read only view of a node in the parse tree of a rule expression
+type Node struct {
+	n *node
+}
+
+// Parse parses a rule expression, e.g. `required & (email | number)`, into a tree of nodes.
+// The names of the rules are looked up in the rules passed in, or the `DefaultRules` if they are nil. A nil node is returned for an empty expression.
+func Parse(expression string, rules Rules) (*Node, error) {
+	if rules == nil {
+		rules = DefaultRules
+	}
+	n, err := newParser().parse(expression, rules)
+	if err != nil {
+		return nil, err
+	} else if n == nil {
+		return nil, nil
+	}
+	return &Node{n}, nil
+}
+
+// Type returns the type of the node
+func (n *Node) Type() NodeType {
+	switch n.n.Type {
+	case typeAnd:
+		return NodeAnd
+	case typeOr:
+		return NodeOr
+	}
+	return NodeRule
+}
+
+// Rule returns the name of the rule of a NodeRule
+func (n *Node) Rule() string {
+	if n.n.Type != typeFunction {
+		return ""
+	}
+	return n.n.Value
+}
+
+// Params returns the params passed to the rule of a NodeRule
+func (n *Node) Params() []Param {
+	return append([]Param(nil), n.n.TypedParams...)
+}
+
+// IsOptional returns true if the rule of a NodeRule is followed by a `?`, and is skipped when the field is empty
+func (n *Node) IsOptional() bool {
+	return n.n.IsOptional
+}
+
+// Children returns the two nodes joined by a NodeAnd or a NodeOr
+func (n *Node) Children() []*Node {
+	if n.n.Type == typeFunction {
+		return nil
+	}
+	return []*Node{{n.n.A}, {n.n.B}}
+}
+
 type parser struct {
 	debug bool
 	cache map[string]*node
@@ -60,7 +132,11 @@ func (p *parser) parseBools(l *lexer, rules map[string]Rule) (*node, error) {
 			// we reached the end of the line and we have a dangling operator eg `t & f &`
 			hasDangelingOperator := !isEmptyNode && (current.Type == typeAnd || current.Type == typeOr) && current.B == nil
 			if hasDangelingOperator {
-				return nil, p.errorf("bad '|' at %d", l.start)
+				operator := "|"
+				if current.Type == typeAnd {
+					operator = "&"
+				}
+				return nil, p.errorAt(l, l.offset(t), t.val, "rule", "bad '%s' at %d (line %d, column %d)", operator, l.start, t.line, t.col)
 			}
 			return current, nil
 		case typeSpace:
@@ -68,20 +144,30 @@ func (p *parser) parseBools(l *lexer, rules map[string]Rule) (*node, error) {
 			continue
 		case typeError:
 			// failed due to a lexing error
-			return nil, p.errorf(t.val)
+			return nil, p.errorAt(l, l.offset(t), l.runeAt(l.offset(t)), "token", "%s (line %d, column %d)", t.val, t.line, t.col)
 		case typeColon, typeComma:
 			// we have bad function syntax, such as `t & : f,`
-			return nil, p.errorf("bad '%s' at %d", t.val, l.start)
-		case typeFunction:
+			expected := "rule"
+			if !isEmptyNode && current.Type == typeFunction {
+				expected = "operator"
+			}
+			return nil, p.errorAt(l, l.offset(t), t.val, expected, "bad '%s' at %d (line %d, column %d)", t.val, l.start, t.line, t.col)
+		case typeFunction, typeString:
 			// check for bad function syntax, such as `t f & t`
 			isOperator := !isEmptyNode && (current.Type == typeAnd || current.Type == typeOr)
 			hasBadFunctionSyntax := !isEmptyNode && !isOperator
 			if hasBadFunctionSyntax {
-				return nil, p.errorf("bad '%s' at %d", t.val, l.start)
+				return nil, p.errorAt(l, l.offset(t), t.val, "operator", "bad '%s' at %d (line %d, column %d)", t.val, l.start, t.line, t.col)
+			}
+
+			// quoted function names can contain any character, e.g. `'my-rule'`
+			name := t.val
+			if t.typ == typeString {
+				name = unquote(unescape(t.val))
 			}
 
 			// parse the function and append it to the tree
-			if n, err := p.parseFunction(l, t.val, rules); err != nil {
+			if n, err := p.parseFunction(l, name, rules); err != nil {
 				return nil, err
 			} else if isEmptyNode {
 				current = n
@@ -90,7 +176,7 @@ func (p *parser) parseBools(l *lexer, rules map[string]Rule) (*node, error) {
 			} else if current.B == nil {
 				current.B = n
 			} else {
-				return nil, p.errorf("bad '%s' at %d", t.val, l.start)
+				return nil, p.errorAt(l, l.offset(t), t.val, "operator", "bad '%s' at %d (line %d, column %d)", t.val, l.start, t.line, t.col)
 			}
 		case typeAnd, typeOr:
 			// check for bad operator syntax, such as `t & & f`
@@ -98,7 +184,7 @@ func (p *parser) parseBools(l *lexer, rules map[string]Rule) (*node, error) {
 			isFull := !isEmptyNode && (current.A != nil && current.B != nil)
 			hasBadOperatorSyntax := isOperator && !isFull
 			if hasBadOperatorSyntax {
-				return nil, p.errorf("bad '%s' at %d", t.val, l.start)
+				return nil, p.errorAt(l, l.offset(t), t.val, "rule", "bad '%s' at %d (line %d, column %d)", t.val, l.start, t.line, t.col)
 			}
 
 			// append the operation to the tree
@@ -110,7 +196,7 @@ func (p *parser) parseBools(l *lexer, rules map[string]Rule) (*node, error) {
 			// check for missing operator syntax such as `t (f | t)` or `(f & t) t`
 			hasMissingOperator := !isEmptyNode && !(current.Type == typeAnd || current.Type == typeOr)
 			if hasMissingOperator {
-				return nil, p.errorf("bad '%s' at %d", t.val, l.start)
+				return nil, p.errorAt(l, l.offset(t), t.val, "operator", "bad '%s' at %d (line %d, column %d)", t.val, l.start, t.line, t.col)
 			}
 
 			// recursively parse the function and append it to the tree
@@ -121,10 +207,10 @@ func (p *parser) parseBools(l *lexer, rules map[string]Rule) (*node, error) {
 			} else if current.A != nil && current.B == nil {
 				current.B = n
 			} else {
-				return nil, p.errorf("bad '(' at %d", l.start)
+				return nil, p.errorAt(l, l.offset(t), t.val, "operator", "bad '(' at %d (line %d, column %d)", l.start, t.line, t.col)
 			}
 		default:
-			return nil, p.errorf("bad '%s' at %d", t.val, l.start)
+			return nil, p.errorAt(l, l.offset(t), t.val, "rule", "bad '%s' at %d (line %d, column %d)", t.val, l.start, t.line, t.col)
 		}
 	}
 }
@@ -134,7 +220,7 @@ func (p *parser) parseFunction(l *lexer, val string, rules map[string]Rule) (*no
 	var n node
 	r, ok := rules[val]
 	if !ok {
-		return nil, p.errorf("'%s' is not a valid rule", val)
+		return nil, p.errorAt(l, l.start, val, "rule", "'%s' is not a valid rule", val)
 	}
 	n.Rule = r
 	n.Type = typeFunction
@@ -151,10 +237,38 @@ func (p *parser) parseFunction(l *lexer, val string, rules map[string]Rule) (*no
 			needsParam = true
 		case typeBool, typeNumber, typeString, typeFunction: /* note: adding `typeFunction` interprets non-quoted strings as string params if possible */
 			if !needsParam {
-				return nil, p.errorf("bad '%s' at %d", t.val, l.start)
+				return nil, p.errorAt(l, l.offset(t), t.val, "operator", "bad '%s' at %d (line %d, column %d)", t.val, l.start, t.line, t.col)
+			}
+			param := Param{
+				Kind:  ParamIdentifier,
+				Value: t.val,
+			}
+			switch t.typ {
+			case typeFunction:
+				if strings.HasPrefix(t.val, "$") {
+					param.Kind = ParamReference
+					param.Value = t.val[1:]
+				}
+			case typeString:
+				t.val = unescape(t.val)
+				param.Kind = ParamString
+				param.Value = t.val[1 : len(t.val)-1]
+			case typeNumber:
+				param = newParam(t.val)
+			case typeBool:
+				param.Kind = ParamBool
+				param.Bool = t.val == "true"
 			}
 			n.Params = append(n.Params, t.val)
+			n.TypedParams = append(n.TypedParams, param)
 			needsParam = false
+		case typeOptional:
+			// a `?` after a rule skips it when the field is empty, e.g. `email?`
+			if needsParam {
+				return nil, p.errorAt(l, l.offset(t), t.val, "param", "bad '%s' at %d (line %d, column %d)", t.val, l.start, t.line, t.col)
+			}
+			n.IsOptional = true
+			return &n, nil
 		case typeSpace:
 			continue
 		default:
@@ -166,6 +280,34 @@ func (p *parser) parseFunction(l *lexer, val string, rules map[string]Rule) (*no
 	return &n, nil
 }
 
+// unescape replaces the escaped quotes and backslashes inside of a string token with the characters they represent
+func unescape(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(`'"\`, s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// errorAt returns a *ParseError for the token at the offset passed in, which was expected to be of the category passed in
+func (p *parser) errorAt(l *lexer, offset int, token, expected, v string, is ...interface{}) error {
+	line, col := l.position(offset)
+	return &ParseError{
+		Offset:   offset,
+		Line:     line,
+		Column:   col,
+		Token:    token,
+		Expected: expected,
+		err:      p.errorf(v, is...),
+	}
+}
+
 // errorf formats the internal error messages related to parsing and executing within the framework
 func (p *parser) errorf(v string, is ...interface{}) error {
 	var tag string
@@ -178,29 +320,62 @@ func (p *parser) errorf(v string, is ...interface{}) error {
 }
 
 type node struct {
-	Rule   Rule      `json:"-"`
-	Params []string  `json:"params,omitempty"`
-	Type   tokenType `json:"type"`
-	Value  string    `json:"value,omitempty"`
-	A      *node     `json:"a,omitempty"`
-	B      *node     `json:"b,omitempty"`
+	Rule        Rule      `json:"-"`
+	Params      []string  `json:"params,omitempty"`
+	TypedParams []Param   `json:"typedParams,omitempty"`
+	Type        tokenType `json:"type"`
+	Value       string    `json:"value,omitempty"`
+	IsOptional  bool      `json:"isOptional,omitempty"`
+	A           *node     `json:"a,omitempty"`
+	B           *node     `json:"b,omitempty"`
 }
 
 func (n *node) execute(ps *RuleParams) error {
 	// execute functions
 	if n.Type == typeFunction {
+		if n.IsOptional && !ps.hasValue(ps.Field) {
+			return nil
+		}
 		ps.Params = n.Params
-		return n.Rule(ps)
+		ps.TypedParams = n.TypedParams
+		if ps.validator == nil || ps.validator.onRuleExecuted == nil {
+			return n.Rule(ps)
+		}
+		start := time.Now()
+		err := n.Rule(ps)
+		ps.validator.onRuleExecuted(n.Value, ps.Path, time.Since(start), err)
+		return err
 	}
 
 	// execute ands and ors
 	err := n.A.execute(ps)
-	if (err == nil && n.Type == typeAnd) || (err != nil && n.Type == typeOr) {
+	if isReportAll := ps.validator != nil && ps.validator.reportAll; isReportAll && err != nil {
+		// execute the other side too, reporting the errors of both sides unless it passes an or
+		errB := n.B.execute(ps)
+		if errB == nil && n.Type == typeOr {
+			return nil
+		} else if errB == nil {
+			return err
+		}
+		var errs FieldErrors
+		errs.Add(err, errB)
+		return errs
+	} else if (err == nil && n.Type == typeAnd) || (err != nil && n.Type == typeOr) {
 		return n.B.execute(ps)
 	}
 	return err
 }
 
+// hasRule returns true if the rule name is used anywhere in the tree
+func (n *node) hasRule(name string) bool {
+	if n == nil {
+		return false
+	} else if n.Type == typeFunction {
+		return n.Value == name
+	}
+	return n.A.hasRule(name) || n.B.hasRule(name)
+}
+
 func (n *node) String() string {
 	bs, err := json.MarshalIndent(n, "|", "	")
 	if err != nil {
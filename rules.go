@@ -1,17 +1,34 @@
 package validator
 
 import (
+	"bufio"
 	"encoding"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"net"
+	"path"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
 )
 
-// Rules are a set of rules that the `Validator` will look up by name in order to appy them to fields in a struct
+// Rules are a set of rules that the `Validator` will look up by name in order to appy them to fields in a struct.
+// Names that contain characters other than letters, numbers, underscores and dots must be quoted in tags, e.g. `validate:"'my-rule'"`
 type Rules map[string]Rule
 
 // Add adds a rule to the map of rules
@@ -19,7 +36,13 @@ func (rs Rules) Add(name string, rule func(*RuleParams) error) {
 	rs[name] = rule
 }
 
-// Rule is a rule that is applied to a field in a struct
+// AddSpec adds a rule declared by a spec to the map of rules
+func (rs Rules) AddSpec(name string, spec RuleSpec) {
+	rs[name] = spec.Rule(name)
+}
+
+// Rule is a rule that is applied to a field in a struct.
+// A rule can report more than one problem with a field by returning `FieldErrors`, each of which is added to the result separately.
 type Rule func(*RuleParams) error
 
 // RuleParams is the set of parameters a rule processes to determine if there was a validation error
@@ -35,50 +58,432 @@ type RuleParams struct {
 	// TODO: add example
 	Params []string
 
+	// TypedParams are the same arguments as Params, with their quotes removed and their kind and value already parsed
+	TypedParams []Param
+
 	// Root is the interface{} that was passed to the Validator.Validate method
 	Root reflect.Value
 
+	// RootType is the type of Root
+	RootType reflect.Type
+
 	// Parent is the struct{} that the Field belongs to. This can be the same as Root if a simple struct was passed in to the Validator.Validate func
 	Parent reflect.Value
 
+	// ParentType is the type of Parent, e.g. for looking up the tags of the Field's siblings
+	ParentType reflect.Type
+
 	// Field is the field on the struct whose value is being validated
 	Field reflect.Value
+
+	// Path is the path to the field from the Root, e.g. `items[0].name`
+	Path string
+
+	// validator is the validator that is executing the rule
+	validator *validator
+}
+
+// ParamKind is the kind of value a param was written as
+type ParamKind int
+
+const (
+	// ParamIdentifier is an unquoted word, e.g. `eq:one`
+	ParamIdentifier = ParamKind(iota)
+
+	// ParamString is a quoted string, e.g. `eq:'hello world'`
+	ParamString
+
+	// ParamNumber is a number, e.g. `number:1,10`
+	ParamNumber
+
+	// ParamBool is `true` or `false`
+	ParamBool
+
+	// ParamReference is the name of another field prefixed with a `$`, e.g. `lt:$Max`. Its Value is the name of the field
+	// without the `$`, and it is replaced by the value of the field when the params of value rules like `eq` are read.
+	// Elements of slices, arrays and maps can be referenced by their index or key, e.g. `lt:$Items[0].Price`
+	ParamReference
+)
+
+// Param is an argument that was passed to a rule
+type Param struct {
+	// Kind is the kind of value the param was written as
+	Kind ParamKind
+
+	// Value is the text of the param without its quotes or escape characters
+	Value string
+
+	// Number is the value of a ParamNumber
+	Number float64
+
+	// Bool is the value of a ParamBool
+	Bool bool
+}
+
+// newParam parses a raw param
+func newParam(raw string) Param {
+	p := Param{
+		Kind:  ParamIdentifier,
+		Value: raw,
+	}
+	if unquoted := unquote(raw); unquoted != raw {
+		p.Kind = ParamString
+		p.Value = unquoted
+	} else if len(raw) > 1 && raw[0] == '$' {
+		p.Kind = ParamReference
+		p.Value = raw[1:]
+	} else if b, err := strconv.ParseBool(raw); err == nil && (raw == "true" || raw == "false") {
+		p.Kind = ParamBool
+		p.Bool = b
+	} else if i, err := strconv.ParseInt(raw, 0, 64); err == nil {
+		p.Kind = ParamNumber
+		p.Number = float64(i)
+	} else if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		p.Kind = ParamNumber
+		p.Number = f
+	}
+	return p
+}
+
+// typedParams returns the TypedParams, parsing the Params if they were not set, e.g. when a rule is called directly
+func (ps *RuleParams) typedParams() []Param {
+	if len(ps.TypedParams) == len(ps.Params) {
+		return ps.TypedParams
+	}
+	typed := make([]Param, len(ps.Params))
+	for i, raw := range ps.Params {
+		typed[i] = newParam(raw)
+	}
+	return typed
+}
+
+// paramValues returns the values of the TypedParams, replacing references to other fields with the values of the fields
+func (ps *RuleParams) paramValues() []string {
+	typed := ps.typedParams()
+	values := make([]string, len(typed))
+	for i, p := range typed {
+		if p.Kind == ParamReference {
+			fValue, _ := ps.reference(p.Value)
+			values[i] = stringify(fValue)
+		} else {
+			values[i] = p.Value
+		}
+	}
+	return values
+}
+
+// reference returns the value and the name of the field referenced by a ParamReference. Pointers are dereferenced, and
+// nil pointers are the zero value of the type they point to
+func (ps *RuleParams) reference(name string) (reflect.Value, string) {
+	fValue, fName := sibling(ps, name)
+	for fValue.Kind() == reflect.Ptr {
+		if fValue.IsNil() {
+			fValue = reflect.Zero(fValue.Type().Elem())
+		} else {
+			fValue = fValue.Elem()
+		}
+	}
+	return fValue, fName
 }
 
 // DefaultRules is the default set of rules the validator will be created with
 var DefaultRules = Rules{
-	"required": Required,
-	"empty":    Empty,
-	"name":     Name,
-	"email":    Email,
-	"password": Password,
-	"number":   Number,
-	"letters":  Letters,
-	"eq":       EQ,
-	"xor":      XOR,
-	"or":       OR,
-	"and":      AND,
-	// TODO: create and add neq, lt, gt, lte, and gte
+	"required":         Required,
+	"empty":            Empty,
+	"name":             Name,
+	"email":            Email,
+	"password":         Password,
+	"number":           Number,
+	"letters":          Letters,
+	"eq":               EQ,
+	"notin":            NotIn,
+	"xor":              XOR,
+	"or":               OR,
+	"and":              AND,
+	"required_with":    RequiredWith,
+	"required_without": RequiredWithout,
+	"contrast":         Contrast,
+	"divisible_by":     DivisibleBy,
+	"dockertag":        DockerTag,
+	"nodive":           NoDive,
+	"wholeseconds":     WholeSeconds,
+	"in":               In,
+	"in_fold":          InFold,
+	"distinct":         Distinct,
+	"degrees":          Degrees,
+	"radians":          Radians,
+	"each":             Each,
+	"enum_for":         EnumFor,
+	"colwidth":         ColWidth,
+	"enum":             Enum,
+	"eqfield":          EQField,
+	"nefield":          NEField,
+	"glob":             Glob,
+	"hex":              Hex,
+	"base64":           Base64,
+	"base32":           Base32,
+	"base58":           Base58,
+	"json":             JSON,
+	"startswith":       StartsWith,
+	"endswith":         EndsWith,
+	"lowercase":        Lowercase,
+	"uppercase":        Uppercase,
+	"notblank":         NotBlank,
+	"strongpassword":   StrongPassword,
+	"latitude":         Latitude,
+	"longitude":        Longitude,
+	"hexcolor":         HexColor,
+	"currency":         Currency,
+	"country":          CountryCode,
+	"semver":           SemVer,
+	"sorted":           Sorted,
+	"lt":               LT,
+	"lte":              LTE,
+	"gt":               GT,
+	"gte":              GTE,
+	"ltfield":          LTField,
+	"ltefield":         LTEField,
+	"gtfield":          GTField,
+	"gtefield":         GTEField,
+	"format":           Format,
+	"runelen":          RuneLen,
+	"inlist":           InList,
+	"normalized":       Normalized,
+	"duration":         Duration,
+	"ext":              Ext,
+	"iban":             IBAN,
+	"multipleof":       MultipleOf,
+	"before":           Before,
+	"after":            After,
+	"bytesize":         ByteSize,
+	"mac":              MAC,
+	"hostname":         Hostname,
+	"pattern":          Pattern,
+	"nonempty":         NonEmpty,
+	"printable":        Printable,
+	"slug":             Slug,
+	"decimal":          Decimal,
 }
 
+// presenceRules are the rules that check whether or not fields are set. Fields that use them are never skipped by `Config.SkipEmpty`
+var presenceRules = []string{"required", "empty", "xor", "or", "and", "required_with", "required_without", "notblank", "nonempty"}
+
 // AddRule adds a rule to the `DefaultRules`
 func AddRule(name string, rule func(*RuleParams) error) {
 	DefaultRules.Add(name, rule)
 }
 
+// RuleSpec declares the number of params a rule requires and the kinds of fields it can be applied to, so that misusing
+// the rule is reported by `CheckSyntax` without the rule having to check for itself.
+//
+// Example
+//  RegisterRuleSpec("prefix", RuleSpec{
+//    MinParams: 1,
+//    MaxParams: 1,
+//    Kinds:     []reflect.Kind{reflect.String},
+//    Fn:        Prefix,
+//  })
+//
+type RuleSpec struct {
+	// MinParams is the least number of params the rule requires
+	MinParams int
+
+	// MaxParams is the most params the rule accepts, or 0 if there is no limit
+	MaxParams int
+
+	// Kinds are the kinds of fields the rule can be applied to. It can be applied to fields of any kind if it is empty
+	Kinds []reflect.Kind
+
+	// Fn is the rule, which is only called with params and fields that match the spec
+	Fn Rule
+}
+
+// Rule returns a rule with the name passed in that panics if it is misused, and calls the Fn of the spec otherwise
+func (spec RuleSpec) Rule(name string) Rule {
+	return func(ps *RuleParams) error {
+		if len(ps.Params) < spec.MinParams {
+			panic(fmt.Errorf("%s requires at least %d parameter(s)", name, spec.MinParams))
+		} else if spec.MaxParams > 0 && len(ps.Params) > spec.MaxParams {
+			panic(fmt.Errorf("%s accepts at most %d parameter(s)", name, spec.MaxParams))
+		}
+		if len(spec.Kinds) == 0 {
+			return spec.Fn(ps)
+		}
+		kinds := make([]string, len(spec.Kinds))
+		for i, kind := range spec.Kinds {
+			if ps.Field.Kind() == kind {
+				return spec.Fn(ps)
+			}
+			kinds[i] = kind.String()
+		}
+		article := "a"
+		if strings.ContainsAny(kinds[0][:1], "aeiou") {
+			article = "an"
+		}
+		if len(kinds) > 1 {
+			kinds = append(kinds[:len(kinds)-2], kinds[len(kinds)-2]+" or "+kinds[len(kinds)-1])
+		}
+		panic(fmt.Errorf("the %s tag must be applied to %s %s", name, article, strings.Join(kinds, ", ")))
+	}
+}
+
+// RegisterRuleSpec adds a rule declared by a spec to the `DefaultRules`
+func RegisterRuleSpec(name string, spec RuleSpec) {
+	DefaultRules.AddSpec(name, spec)
+}
+
+// RegisterInterfaceRule adds a rule to the `DefaultRules` that returns an error if the field doesn't implement the interface
+// passed in, e.g. to check that a plugin satisfies its contract. Fields whose type is an interface are checked by the value
+// they hold, and are skipped when they are nil. Other fields are checked by their type or a pointer to it, since pointer fields
+// are dereferenced before their rules are applied, so a *T field passes when T's methods have pointer receivers. Applying the
+// rule to a field whose type can never implement the interface is reported by `CheckSyntax`. It panics if the type passed in
+// isn't an interface.
+//
+// Example
+//  validator.RegisterInterfaceRule("plugin", reflect.TypeOf((*Plugin)(nil)).Elem())
+//
+//  type Struct struct {
+//    Field  interface{} `json:"field" validate:"plugin"` // 'field' must implement Plugin
+//  }
+//
+func RegisterInterfaceRule(name string, iface reflect.Type) {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		panic(fmt.Errorf("%s must be registered with an interface type", name))
+	}
+	DefaultRules.Add(name, implements(name, iface))
+}
+
+// implements returns a rule that checks if a field implements an interface
+func implements(name string, iface reflect.Type) Rule {
+	return func(ps *RuleParams) error {
+		fType := ps.Field.Type()
+		if fType.Kind() != reflect.Interface && !fType.Implements(iface) && !reflect.PtrTo(fType).Implements(iface) {
+			panic(fmt.Errorf("the %s tag must be applied to an interface or a type that implements %s", name, iface))
+		} else if fType.Kind() != reflect.Interface {
+			return nil
+		} else if ps.Field.IsNil() || ps.Field.Elem().Type().Implements(iface) {
+			return nil
+		}
+		return errorf(ps.Tag, message.Key("implements", "'%s' must implement %s"), ps.FieldName, iface.Name())
+	}
+}
+
+// sets are the named sets of values registered with `RegisterSet`
+var sets = map[string][]string{}
+
+// RegisterSet registers a named set of values that can be referenced by the `in` and `in_fold` rules.
+// Sets should be registered before any validation takes place, e.g. in an init func.
+func RegisterSet(name string, values []string) {
+	sets[name] = values
+}
+
+// setMaps are the named maps of sets registered with `RegisterSetMap`
+var setMaps = map[string]map[string][]string{}
+
+// RegisterSetMap registers a named map of sets that can be referenced by the `enum_for` rule. The map is keyed by the
+// possible values of another field, and each key's set contains the values that are allowed when that field has that value.
+// Set maps should be registered before any validation takes place, e.g. in an init func.
+func RegisterSetMap(name string, setMap map[string][]string) {
+	setMaps[name] = setMap
+}
+
+// lists are the named lists registered with `RegisterList`. They're kept apart from the sets registered with `RegisterSet`,
+// because they're read from a reader the first time they're used and are looked up in a map instead of being scanned
+var lists = map[string]*list{}
+
+// list is a named list of values that is read the first time it is used
+type list struct {
+	once   sync.Once
+	r      io.Reader
+	values map[string]bool
+	err    error
+}
+
+// RegisterList registers a named list of newline separated values that can be referenced by the `inlist` rule. The values
+// are read from the reader the first time the list is used, ignoring blank lines and surrounding white space. It is intended
+// for lists that are too long to write in a tag, like reserved usernames. Lists should be registered before any validation
+// takes place, e.g. in an init func.
+func RegisterList(name string, values io.Reader) {
+	lists[name] = &list{r: values}
+}
+
+// contains returns true if the value is in the list, reading the list if it hasn't been read yet
+func (l *list) contains(value string) (bool, error) {
+	l.once.Do(func() {
+		l.values = make(map[string]bool)
+		scanner := bufio.NewScanner(l.r)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); len(line) > 0 {
+				l.values[line] = true
+			}
+		}
+		l.err = scanner.Err()
+		l.r = nil
+	})
+	return l.values[value], l.err
+}
+
+// formats are the named string formats that can be checked by the `format` rule
+var formats = map[string]func(string) bool{
+	"email":    emailAddress.MatchString,
+	"e164":     e164.MatchString,
+	"hexcolor": hexColor.MatchString,
+	"semver":   semVer.MatchString,
+	"json": func(s string) bool {
+		return json.Valid([]byte(s))
+	},
+	"rfc3339": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"ipv4": func(s string) bool {
+		return !strings.Contains(s, ":") && net.ParseIP(s) != nil
+	},
+	"ipv6": func(s string) bool {
+		return strings.Contains(s, ":") && net.ParseIP(s) != nil
+	},
+}
+
+// RegisterFormat registers a named string format that can be checked by the `format` rule, e.g. `format:isbn`.
+// Formats should be registered before any validation takes place, e.g. in an init func.
+func RegisterFormat(name string, fn func(string) bool) {
+	formats[name] = fn
+}
+
+// RegisterEnum registers the allowed values of a named enum that can be referenced by the `enum` rule. Enums are stored with
+// the sets registered with `RegisterSet`, so an enum can be referenced by the `in` rule, and a set by the `enum` rule.
+// Enums should be registered before any validation takes place, e.g. in an init func.
+func RegisterEnum(name string, values []string) {
+	RegisterSet(name, values)
+}
+
+// patterns are the named regular expressions registered with `RegisterPattern`
+var patterns = map[string]*regexp.Regexp{}
+
+// RegisterPattern compiles a regular expression and registers it under a name that can be referenced by the `pattern` rule,
+// so that it isn't recompiled every time it's used and doesn't have to be quoted inside of a tag. Like `regexp.MustCompile`,
+// it panics if the expression can't be compiled. Patterns should be registered before any validation takes place, e.g. in an init func.
+func RegisterPattern(name, pattern string) {
+	patterns[name] = regexp.MustCompile(pattern)
+}
+
 // Required returns an error if the filed contains the zero value of the type or nil.
+// Channels and funcs are only checked for nil, so they can be required like any other field.
+// A time.Time is unset if it is the zero time in any location, e.g. `time.Time{}.Local()`.
 //
 // Example
 //  type Struct struct {
-//    Field  string `json:"field" validate:"required"` // 'field' is required
+//    Field  string        `json:"field" validate:"required"`  // 'field' is required
+//    Field2 chan struct{} `json:"field2" validate:"required"` // 'field2' is required
+//    Field3 time.Time     `json:"field3" validate:"required"` // 'field3' is required
 //  }
 //
 func Required(ps *RuleParams) error {
 	field, tag, fieldName := ps.Field, ps.Tag, ps.FieldName
-	if hasValue(field) {
+	if ps.hasValue(field) {
 		return nil
 	}
-	return errorf(tag, "'%s' is required", fieldName)
+	return errorf(tag, message.Key("required", "'%s' is required"), fieldName)
 }
 
 // Empty returns an error if the field is not empty. It should be 'or'd together with
@@ -91,10 +496,10 @@ func Required(ps *RuleParams) error {
 //
 func Empty(ps *RuleParams) error {
 	field, tag, fieldName := ps.Field, ps.Tag, ps.FieldName
-	if !hasValue(field) {
+	if !ps.hasValue(field) {
 		return nil
 	}
-	return errorf(tag, "'%s' should position omitempty before other tags", fieldName)
+	return errorf(tag, message.Key("empty", "'%s' should position omitempty before other tags"), fieldName)
 }
 
 // Name returns an error if the field doesn't contain a valid name
@@ -115,46 +520,67 @@ func Name(ps *RuleParams) error {
 	if isValid, _ := regexp.Match(`^[^0-9_!¡?÷?¿/\\+=@#$%ˆ&*(){}|~<>;:[\]]{2,}$`, []byte(ps.Field.String())); isValid {
 		return nil
 	}
-	if len(ps.Params) > 0 {
-		return fmt.Errorf("%+v", ps.Params[0])
+	if params := ps.typedParams(); len(params) > 0 {
+		return fmt.Errorf("%+v", params[0].Value)
 	}
-	return errorf(ps.Tag, "'%s' must be a valid name", ps.FieldName)
+	return errorf(ps.Tag, message.Key("name", "'%s' must be a valid name"), ps.FieldName)
 }
 
-// Email returns an error if the field doesn't contain a valid email address
+// Email returns an error if the field doesn't contain a valid email address. Passing in the `strict` param also enforces
+// the RFC 5321 length limits of 64 characters for the local part and 254 characters in total, and rejects consecutive dots.
 //
 // Example
 //  type Struct struct {
-//    Field  string `json:"field" validate:"email"` // 'field' must be a valid email address
+//    Field  string `json:"field" validate:"email"`         // 'field' must be a valid email address
+//    Field2 string `json:"field2" validate:"email:strict"` // 'field2' must be a valid email address
 //  }
 //
 func Email(ps *RuleParams) error {
 	if ps.Field.Kind() != reflect.String {
 		panic("the email tag must be applied to a string")
 	}
-	if isValid, _ := regexp.Match(`^(([^<>()[\]\\.,;:\s@"]+(\.[^<>()[\]\\.,;:\s@"]+)*)|(".+"))@((\[[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}])|(([a-zA-Z\-0-9]+\.)+[a-zA-Z]{2,}))$`, []byte(ps.Field.String())); isValid {
+	isStrict := false
+	for _, p := range ps.typedParams() {
+		if p.Value != "strict" {
+			panic(fmt.Errorf("'%s' is not a valid param for email", p.Value))
+		}
+		isStrict = true
+	}
+	email := ps.Field.String()
+	if emailAddress.MatchString(email) && (!isStrict || isDeliverable(email)) {
 		return nil
 	}
-	return errorf(ps.Tag, "'%s' must be a valid email address", ps.FieldName)
+	return errorf(ps.Tag, message.Key("email", "'%s' must be a valid email address"), ps.FieldName)
+}
+
+// isDeliverable returns true if the email address is within the RFC 5321 length limits and doesn't contain consecutive dots
+func isDeliverable(email string) bool {
+	local := email[:strings.LastIndex(email, "@")]
+	return len(local) <= 64 && len(email) <= 254 && !strings.Contains(email, "..")
 }
 
-// Password returns an error if the field doesn't contain a valid password
+// Password returns an error if the field doesn't contain a valid password. Each of the criteria the password fails is reported separately.
 // Example
 //  type Struct struct {
-//    Field  string `json:"field" validate:"password"` // 'field' must be a valid password
+//    Field  string `json:"field" validate:"password"` // 'field' must be at least 6 characters long, 'field' must contain at least one number or special character (eg. @!#)
 //  }
 //
 func Password(ps *RuleParams) error {
 	if ps.Field.Kind() != reflect.String {
 		panic("the password tag must be applied to a string")
 	}
+	var errs FieldErrors
 	field := ps.Field.String()
-	isLongEnough := len(field) >= 6
-	hasSpecialCharacters, _ := regexp.Match(`[^a-zA-Z]+`, []byte(field))
-	if isLongEnough && hasSpecialCharacters {
-		return nil
+	if isLongEnough := len(field) >= 6; !isLongEnough {
+		errs = append(errs, errorf(ps.Tag, message.Key("password.length", "'%s' must be at least 6 characters long"), ps.FieldName))
+	}
+	if hasSpecialCharacters, _ := regexp.Match(`[^a-zA-Z]+`, []byte(field)); !hasSpecialCharacters {
+		errs = append(errs, errorf(ps.Tag, message.Key("password.special", "'%s' must contain at least one number or special character (eg. @!#)"), ps.FieldName))
 	}
-	return errorf(ps.Tag, "'%s' must be a at least 6 characters long and contain at least one number or special character (eg. @!#)", ps.FieldName)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 // Number retuns an error if the field doesn't contain numbers only
@@ -169,7 +595,7 @@ func Password(ps *RuleParams) error {
 func Number(ps *RuleParams) error {
 	var min, max, i int
 	var isMinSet, isMaxSet bool
-	params, field, tag, fieldName := ps.Params, ps.Field, ps.Tag, ps.FieldName
+	params, field, tag, fieldName := ps.paramValues(), ps.Field, ps.Tag, ps.FieldName
 
 	// parse min params
 	if len(params) > 0 && len(params[0]) > 0 {
@@ -195,26 +621,26 @@ func Number(ps *RuleParams) error {
 	case reflect.String:
 		str := field.String()
 		if isValid, _ := regexp.Match("^[0-9]+$", []byte(str)); !isValid {
-			return errorf(tag, "'%s' must contain only numbers", fieldName)
+			return errorf(tag, message.Key("number", "'%s' must contain only numbers"), fieldName)
 		} else if i := len(str); (!isMinSet || i >= min) && (!isMaxSet || i <= max) {
 			return nil
 		} else if isMaxSet && isMinSet {
-			return errorf(tag, "'%s' must be %d to %d digits", fieldName, min, max)
+			return errorf(tag, message.Key("number.digits", "'%s' must be %d to %d digits"), fieldName, min, max)
 		} else if isMaxSet {
-			return errorf(tag, "'%s' must have %d or fewer digits", fieldName, max)
+			return errorf(tag, message.Key("number.digits.max", "'%s' must have %d or fewer digits"), fieldName, max)
 		} else if isMinSet {
-			return errorf(tag, "'%s' must have %d or more digits", fieldName, min)
+			return errorf(tag, message.Key("number.digits.min", "'%s' must have %d or more digits"), fieldName, min)
 		}
 	}
 
 	if (!isMinSet || i >= min) && (!isMaxSet || i <= max) {
 		return nil
 	} else if isMaxSet && isMinSet {
-		return errorf(tag, "'%s' must be %d to %d", fieldName, min, max)
+		return errorf(tag, message.Key("number.range", "'%s' must be %d to %d"), fieldName, min, max)
 	} else if isMaxSet {
-		return errorf(tag, "'%s' must be %d or less", fieldName, max)
+		return errorf(tag, message.Key("number.max", "'%s' must be %d or less"), fieldName, max)
 	} else if isMinSet {
-		return errorf(tag, "'%s' must be %d or more", fieldName, min)
+		return errorf(tag, message.Key("number.min", "'%s' must be %d or more"), fieldName, min)
 	}
 
 	return nil
@@ -234,10 +660,11 @@ func Letters(ps *RuleParams) error {
 			return nil
 		}
 	}
-	return errorf(tag, "'%s' can only contain letters and spaces", fieldName)
+	return errorf(tag, message.Key("letters", "'%s' can only contain letters and spaces"), fieldName)
 }
 
-// EQ returns an error if the field does not == one of the params passed in
+// EQ returns an error if the field does not == one of the params passed in. Fields that implement encoding.TextMarshaler
+// are compared by their text, and time.Time fields are equal to dates and RFC3339 times at the same instant in any time zone.
 //
 // Example
 //  type Struct struct {
@@ -245,56 +672,93 @@ func Letters(ps *RuleParams) error {
 //  }
 //
 func EQ(ps *RuleParams) error {
-	params, field, tag, fieldName := ps.Params, ps.Field, ps.Tag, ps.FieldName
+	params, field, tag, fieldName := ps.paramValues(), ps.Field, ps.Tag, ps.FieldName
 	psLen := len(params)
 	if psLen == 0 {
 		panic(fmt.Errorf("eq requires at least one parameter"))
 	}
+	if equalsParam(field, params) {
+		return nil
+	}
+
+	// construct the error message
+	context := []string{fieldName}
+	context = append(context, params...)
+	return errorTemplate(tag, message.Key("eq", `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 1}} must equal {{else if eq $i $last}} or {{else if gt $i 0}}, {{end}}'{{$field}}'{{end}}`), context)
+}
+
+// NotIn returns an error if the field == one of the params passed in. It is the inverse of `eq`
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"notin:admin,root,system"` // 'field' must not be one of 'admin', 'root' or 'system'
+//  }
+//
+func NotIn(ps *RuleParams) error {
+	params := ps.paramValues()
+	if len(params) == 0 {
+		panic(fmt.Errorf("notin requires at least one parameter"))
+	}
+	if !equalsParam(ps.Field, params) {
+		return nil
+	}
+	context := append([]string{ps.FieldName}, params...)
+	return errorTemplate(ps.Tag, message.Key("notin", `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 1}}{{if eq $len 2}} must not equal {{else}} must not be one of {{end}}{{else if eq $i $last}} or {{else if gt $i 0}}, {{end}}'{{$field}}'{{end}}`), context)
+}
 
-	// parse the params to match the kind of field and compare for equality
+// equalsParam parses the params to match the kind of field and returns true if the field equals any of them
+func equalsParam(field reflect.Value, params []string) bool {
 	switch field.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		for _, p := range params {
 			if i, err := strconv.ParseInt(p, 10, 0); err == nil && field.Int() == i {
-				return nil
+				return true
 			}
 		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		for _, p := range params {
 			if i, err := strconv.ParseUint(p, 10, 0); err == nil && field.Uint() == i {
-				return nil
+				return true
 			}
 		}
 	case reflect.Float32, reflect.Float64:
 		for _, p := range params {
 			i, err := strconv.ParseFloat(p, 64)
 			if err == nil && field.Float() == i {
-				return nil
+				return true
 			}
 		}
 	case reflect.String:
 		for _, p := range params {
 			if p == field.String() {
-				return nil
+				return true
+			}
+		}
+	case reflect.Struct:
+		if field.Type() == timeType {
+			for _, p := range params {
+				if t, err := time.Parse("2006-01-02", p); err == nil && field.Interface().(time.Time).Equal(t) {
+					return true
+				} else if t, err := time.Parse(time.RFC3339, p); err == nil && field.Interface().(time.Time).Equal(t) {
+					return true
+				}
 			}
 		}
 	}
 
 	// if the field implements encoding.TextMarshaler, check the text value of the field as well
-	if marshaler, ok := field.Interface().(encoding.TextMarshaler); ok {
+	if field.Kind() == reflect.Ptr && field.IsNil() {
+		return false
+	} else if marshaler, ok := field.Interface().(encoding.TextMarshaler); ok {
 		if text, err := marshaler.MarshalText(); err == nil {
 			for _, p := range params {
 				if p == string(text) {
-					return nil
+					return true
 				}
 			}
 		}
 	}
-
-	// construct the error message
-	context := []string{fieldName}
-	context = append(context, params...)
-	return errorTemplate(tag, `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 1}} must equal {{else if eq $i $last}} or {{else if gt $i 0}}, {{end}}'{{$field}}'{{end}}`, context)
+	return false
 }
 
 // XOR returns an error when more than one or zero of either the field that it is applied to or any of the field names passed as params are set to a non zero value
@@ -306,37 +770,28 @@ func EQ(ps *RuleParams) error {
 //  }
 //
 func XOR(ps *RuleParams) error {
-	params, parent, field, tag, fieldName := ps.Params, ps.Parent, ps.Field, ps.Tag, ps.FieldName
+	params, field, tag, fieldName := ps.Params, ps.Field, ps.Tag, ps.FieldName
 	fieldNames := []string{fieldName}
-	pType := parent.Type()
 	var populated int
-	if hasValue(field) {
+	if ps.hasValue(field) {
 		populated++
 	}
 	for _, param := range params {
-		fField, ok := pType.FieldByName(param)
-		fValue := parent.FieldByName(param)
-		if !ok || !fValue.IsValid() {
-			panic(fmt.Errorf("'%s.%s' is not a valid field", parent.Type().Name(), param))
-		}
+		fValue, fName := sibling(ps, param)
 
 		// count every field that is populated
-		if hasValue(fValue) {
+		if ps.hasValue(fValue) {
 			populated++
 		}
 
 		// write the json names of the other fields into the potential error message context
-		if fieldName, ok := fField.Tag.Lookup("json"); ok {
-			fieldNames = append(fieldNames, strings.Split(fieldName, ",")[0])
-		} else {
-			fieldNames = append(fieldNames, fField.Name)
-		}
+		fieldNames = append(fieldNames, fName)
 	}
 	if populated == 1 {
 		return nil
 	}
 
-	return errorTemplate(tag, `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 0}}either {{else if eq $i $last}} or {{else}}, {{end}}'{{$field}}'{{end}} must be set`, fieldNames)
+	return errorTemplate(tag, message.Key("xor", `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 0}}either {{else if eq $i $last}} or {{else}}, {{end}}'{{$field}}'{{end}} must be set`), fieldNames)
 }
 
 // OR returns an error when neither the field that it is applied to nor any of the field names passed as params are set to a non zero value
@@ -348,32 +803,23 @@ func XOR(ps *RuleParams) error {
 //  }
 //
 func OR(ps *RuleParams) error {
-	params, parent, field, tag, fieldName := ps.Params, ps.Parent, ps.Field, ps.Tag, ps.FieldName
+	params, field, tag, fieldName := ps.Params, ps.Field, ps.Tag, ps.FieldName
 
-	pType := parent.Type()
-	if hasValue(field) {
+	if ps.hasValue(field) {
 		return nil
 	}
 	fieldNames := []string{fieldName}
 	for _, param := range params {
-		fField, ok := pType.FieldByName(param)
-		fValue := parent.FieldByName(param)
-		if !ok || !fValue.IsValid() {
-			panic(fmt.Errorf("'%s.%s' is not a valid field", parent.Type().Name(), param))
-		}
-		if hasValue(fValue) {
+		fValue, fName := sibling(ps, param)
+		if ps.hasValue(fValue) {
 			return nil
 		}
 
 		// write the json names of the other fields into the potential error message
-		if fieldName, ok := fField.Tag.Lookup("json"); ok {
-			fieldNames = append(fieldNames, strings.Split(fieldName, ",")[0])
-		} else {
-			fieldNames = append(fieldNames, fField.Name)
-		}
+		fieldNames = append(fieldNames, fName)
 	}
 
-	return errorTemplate(tag, `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 0}}either {{else if eq $i $last}} and/or {{else}}, {{end}}'{{$field}}'{{end}} must be set`, fieldNames)
+	return errorTemplate(tag, message.Key("or", `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i 0}}either {{else if eq $i $last}} and/or {{else}}, {{end}}'{{$field}}'{{end}} must be set`), fieldNames)
 }
 
 // AND returns an error when the field that it is applied to or any of the field names passed as params are set to the zero value
@@ -385,38 +831,2357 @@ func OR(ps *RuleParams) error {
 //  }
 //
 func AND(ps *RuleParams) error {
-	params, parent, field, tag, fieldName := ps.Params, ps.Parent, ps.Field, ps.Tag, ps.FieldName
+	params, field, tag, fieldName := ps.Params, ps.Field, ps.Tag, ps.FieldName
 	fieldNames := []string{fieldName}
-	pType := parent.Type()
-	isPopulated := hasValue(field)
+	isPopulated := ps.hasValue(field)
 	for _, param := range params {
-		fField, ok := pType.FieldByName(param)
-		fValue := parent.FieldByName(param)
-		if !ok || !fValue.IsValid() {
-			panic(fmt.Errorf("'%s.%s' is not a valid field", parent.Type().Name(), param))
-		}
-		isPopulated = isPopulated && hasValue(fValue)
+		fValue, fName := sibling(ps, param)
+		isPopulated = isPopulated && ps.hasValue(fValue)
 
 		// write the json names of the other fields into the potential error message
-		if fieldName, ok := fField.Tag.Lookup("json"); ok {
-			fieldNames = append(fieldNames, strings.Split(fieldName, ",")[0])
-		} else {
-			fieldNames = append(fieldNames, fField.Name)
-		}
+		fieldNames = append(fieldNames, fName)
 	}
 	if isPopulated {
 		return nil
 	}
-	return errorTemplate(tag, `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i $last}} and {{else if gt $i 0}}, {{end}}'{{$field}}'{{end}} must be set`, fieldNames)
+	return errorTemplate(tag, message.Key("and", `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $field := .}}{{if eq $i $last}} and {{else if gt $i 0}}, {{end}}'{{$field}}'{{end}} must be set`), fieldNames)
 }
 
-// hasValue returns if the field is not nil or the golang devault/zero value
-func hasValue(field reflect.Value) bool {
-	fieldType := field.Type()
-	fieldKind := fieldType.Kind()
-	switch fieldKind {
-	case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
-		return !field.IsNil()
+// RequiredWith returns an error if the field is not set when any of the field names passed as params are set
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"required_with:Field2,Field3"` // 'field' is required
+//    Field2 string
+//    Field3 string
+//  }
+//
+func RequiredWith(ps *RuleParams) error {
+	if len(ps.Params) == 0 {
+		panic(fmt.Errorf("required_with requires at least one field name"))
+	}
+	isRequired := false
+	for _, param := range ps.Params {
+		fValue, _ := sibling(ps, param)
+		isRequired = isRequired || ps.hasValue(fValue)
+	}
+	if !isRequired {
+		return nil
+	}
+	return Required(ps)
+}
+
+// RequiredWithout returns an error if the field is not set when any of the field names passed as params are not set
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"required_without:Field2"` // 'field' is required
+//    Field2 string
+//  }
+//
+func RequiredWithout(ps *RuleParams) error {
+	if len(ps.Params) == 0 {
+		panic(fmt.Errorf("required_without requires at least one field name"))
+	}
+	isRequired := false
+	for _, param := range ps.Params {
+		fValue, _ := sibling(ps, param)
+		isRequired = isRequired || !ps.hasValue(fValue)
+	}
+	if !isRequired {
+		return nil
+	}
+	return Required(ps)
+}
+
+// Contrast returns an error if the field doesn't contain a hex color that meets the WCAG contrast ratio passed in
+// against the background color passed in. Hex colors can be in either the #RGB or #RRGGBB format.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"contrast:'#ffffff',4.5"` // 'field' must have a contrast ratio of at least 4.5 against #ffffff
+//  }
+//
+func Contrast(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the contrast tag must be applied to a string")
+	}
+	if len(ps.Params) != 2 {
+		panic(fmt.Errorf("contrast requires a background color and a ratio"))
+	}
+
+	// parse the background and the ratio params
+	background, ratio := unquote(ps.Params[0]), unquote(ps.Params[1])
+	bg, ok := parseHexColor(background)
+	if !ok {
+		panic(fmt.Errorf("contrast requires a valid background color, got '%s'", background))
+	}
+	min, err := strconv.ParseFloat(ratio, 64)
+	if err != nil || min < 1 || min > 21 {
+		panic(fmt.Errorf("contrast requires a ratio between 1 and 21, got '%s'", ratio))
+	}
+
+	// compare the contrast ratio of the field to the background
+	if fg, ok := parseHexColor(ps.Field.String()); ok && contrastRatio(fg, bg) >= min {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("contrast", "'%s' must have a contrast ratio of at least %s against %s"), ps.FieldName, ratio, background)
+}
+
+// DivisibleBy returns an error if the integer field is not a multiple of the integer field whose name is passed in as a param.
+// A divisor of zero is never satisfied. It's a validation error rather than a panic, because the divisor is the value of a
+// field and not part of the tag, so a zero divisor isn't a syntax error (`CheckSyntax` is often run on zero values).
+//
+// Example
+//  type Struct struct {
+//    Field  int `json:"field" validate:"divisible_by:Field2"` // 'field' must be a multiple of 'field2'
+//    Field2 int `json:"field2"`
+//  }
+//
+func DivisibleBy(ps *RuleParams) error {
+	if len(ps.Params) != 1 {
+		panic(fmt.Errorf("divisible_by requires exactly one field name"))
+	}
+	dividend, ok := magnitude(ps.Field)
+	if !ok {
+		panic("the divisible_by tag must be applied to an integer")
+	}
+	fValue, fName := sibling(ps, ps.Params[0])
+	divisor, ok := magnitude(fValue)
+	if !ok {
+		panic(fmt.Errorf("'%s' must be an integer to be used by divisible_by", ps.Params[0]))
+	}
+	if divisor != 0 && dividend%divisor == 0 {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("divisible_by", "'%s' must be a multiple of '%s'"), ps.FieldName, fName)
+}
+
+// DockerTag returns an error if the field doesn't contain a valid docker image reference
+// in the `[registry/]namespace/name[:tag][@digest]` format. Repository names must be lowercase.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"dockertag"` // 'field' must be a valid image reference
+//  }
+//
+func DockerTag(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the dockertag tag must be applied to a string")
+	}
+	if matches := dockerReference.FindStringSubmatch(ps.Field.String()); matches != nil {
+		domain, path := matches[1], matches[2]
+
+		// the first component is only a registry if it looks like a host, otherwise it's part of the path
+		isHost := strings.ContainsAny(domain, ".:") || domain == "localhost"
+		if len(domain) > 0 && !isHost {
+			path = domain + "/" + path
+		}
+		if len(path) <= 255 && (isHost || dockerPath.MatchString(path)) {
+			return nil
+		}
+	}
+	return errorf(ps.Tag, message.Key("dockertag", "'%s' must be a valid image reference"), ps.FieldName)
+}
+
+// NoDive never returns an error. It prevents the validator from validating the fields nested inside of
+// the field it is applied to, while the rest of the field's rules still run. To skip a field entirely, use `validate:"-"`.
+//
+// Example
+//  type Struct struct {
+//    Field  ThirdParty `json:"field" validate:"nodive"`            // fields inside of 'field' are not validated
+//    Field2 *Nested    `json:"field2" validate:"required & nodive"` // 'field2' is required, but fields inside of it are not validated
+//    Field3 Nested     `json:"field3" validate:"-"`                 // 'field3' is ignored
+//  }
+//
+func NoDive(ps *RuleParams) error {
+	return nil
+}
+
+// WholeSeconds returns an error if the time.Time field has a non zero sub-second component
+//
+// Example
+//  type Struct struct {
+//    Field  time.Time `json:"field" validate:"wholeseconds"` // 'field' must not have sub-second precision
+//  }
+//
+func WholeSeconds(ps *RuleParams) error {
+	if ps.Field.Type() != timeType {
+		panic("the wholeseconds tag must be applied to a time.Time")
+	}
+	if ps.Field.Interface().(time.Time).Nanosecond() == 0 {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("wholeseconds", "'%s' must not have sub-second precision"), ps.FieldName)
+}
+
+// In returns an error if the field is not one of the values in the set registered with `RegisterSet` under the name passed in
+//
+// Example
+//  validator.RegisterSet("roles", []string{"admin", "user"})
+//
+//  type Struct struct {
+//    Field  string `json:"field" validate:"in:roles"` // 'field' must be one of the allowed roles
+//  }
+//
+func In(ps *RuleParams) error {
+	return in(ps, "in", func(a, b string) bool {
+		return a == b
+	})
+}
+
+// InFold returns an error if the field is not one of the values in the set registered with `RegisterSet` under the name passed in,
+// ignoring case
+//
+// Example
+//  validator.RegisterSet("roles", []string{"admin", "user"})
+//
+//  type Struct struct {
+//    Field  string `json:"field" validate:"in_fold:roles"` // 'field' must be one of the allowed roles, e.g. "ADMIN" or "admin"
+//  }
+//
+func InFold(ps *RuleParams) error {
+	return in(ps, "in_fold", strings.EqualFold)
+}
+
+// in implements `In` and `InFold`
+func in(ps *RuleParams, rule string, equal func(a, b string) bool) error {
+	if ps.Field.Kind() != reflect.String {
+		panic(fmt.Errorf("the %s tag must be applied to a string", rule))
+	}
+	if len(ps.Params) != 1 {
+		panic(fmt.Errorf("%s requires exactly one set name", rule))
+	}
+	name := unquote(ps.Params[0])
+	set, ok := sets[name]
+	if !ok {
+		panic(fmt.Errorf("'%s' is not a registered set", name))
+	}
+	field := ps.Field.String()
+	for _, value := range set {
+		if equal(field, value) {
+			return nil
+		}
+	}
+	return errorf(ps.Tag, message.Key(rule, "'%s' must be one of the allowed %s"), ps.FieldName, name)
+}
+
+// Distinct returns an error if the slice or array field contains the same element more than once
+//
+// Example
+//  type Struct struct {
+//    Field  []string `json:"field" validate:"distinct"` // 'field' must not contain duplicates
+//  }
+//
+func Distinct(ps *RuleParams) error {
+	field := ps.Field
+	if kind := field.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		panic("the distinct tag must be applied to a slice or an array")
+	}
+	for i, l := 0, field.Len(); i < l; i++ {
+		for j := i + 1; j < l; j++ {
+			if reflect.DeepEqual(field.Index(i).Interface(), field.Index(j).Interface()) {
+				return errorf(ps.Tag, message.Key("distinct", "'%s' must not contain duplicates"), ps.FieldName)
+			}
+		}
+	}
+	return nil
+}
+
+// Degrees returns an error if the float field is not an angle between 0 and 360 degrees.
+// Passing the `wrap` param accepts any finite angle, so that it can be normalized later.
+//
+// Example
+//  type Struct struct {
+//    Field  float64 `json:"field" validate:"degrees"`       // 'field' must be between 0 and 360 degrees
+//    Field2 float64 `json:"field2" validate:"degrees:wrap"` // 'field2' can be any angle
+//  }
+//
+func Degrees(ps *RuleParams) error {
+	return angle(ps, "degrees", 360, message.Key("degrees", "'%s' must be between 0 and 360 degrees"))
+}
+
+// Radians returns an error if the float field is not an angle between 0 and 2π radians.
+// Passing the `wrap` param accepts any finite angle, so that it can be normalized later.
+//
+// Example
+//  type Struct struct {
+//    Field  float64 `json:"field" validate:"radians"`       // 'field' must be between 0 and 2π radians
+//    Field2 float64 `json:"field2" validate:"radians:wrap"` // 'field2' can be any angle
+//  }
+//
+func Radians(ps *RuleParams) error {
+	return angle(ps, "radians", 2*math.Pi, message.Key("radians", "'%s' must be between 0 and 2π radians"))
+}
+
+// angle implements `Degrees` and `Radians`
+func angle(ps *RuleParams, rule string, max float64, key message.Reference) error {
+	if kind := ps.Field.Kind(); kind != reflect.Float32 && kind != reflect.Float64 {
+		panic(fmt.Errorf("the %s tag must be applied to a float", rule))
+	}
+	var isWrapped bool
+	for _, p := range ps.typedParams() {
+		if p.Value != "wrap" {
+			panic(fmt.Errorf("'%s' is not a valid param for %s", p.Value, rule))
+		}
+		isWrapped = true
+	}
+	f := ps.Field.Float()
+	if isWrapped && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return nil
+	} else if f >= 0 && f <= max {
+		return nil
+	}
+	return errorf(ps.Tag, key, ps.FieldName)
+}
+
+// Each returns an error for every element of the slice, array or map field that doesn't pass the rule expression passed in.
+// The expression must be quoted and can use any of the validator's rules, including `&`, `|` and `()`.
+//
+// Example
+//  type Struct struct {
+//    Field  []string `json:"field" validate:"each:'email'"`             // 'field[0]' must be a valid email address
+//    Field2 []string `json:"field2" validate:"each:'empty | letters'"` // 'field2[0]' can only contain letters and spaces
+//  }
+//
+func Each(ps *RuleParams) error {
+	field := ps.Field
+	kind := field.Kind()
+	if kind != reflect.Slice && kind != reflect.Array && kind != reflect.Map {
+		panic("the each tag must be applied to a slice, an array or a map")
+	}
+	if len(ps.Params) != 1 {
+		panic(fmt.Errorf("each requires exactly one rule expression"))
+	}
+	parsed, err := ps.parse(unquote(ps.Params[0]))
+	if err != nil {
+		panic(err)
+	}
+
+	// validate every element with the parsed expression
+	var errs FieldErrors
+	validate := func(index string, element reflect.Value) {
+		// nil pointers are the zero value of the type they point to, the same as the pointer fields of a struct
+		if element.Kind() == reflect.Ptr && element.IsNil() {
+			element = reflect.Zero(element.Type().Elem())
+		} else if element.Kind() == reflect.Ptr {
+			element = element.Elem()
+		}
+		eps := *ps
+		eps.Field = element
+		eps.FieldName = ps.FieldName + index
+		eps.Path = ps.Path + index
+		if err := parsed.execute(&eps); err != nil {
+			errs.addField(eps.Path, err)
+		}
+	}
+	if kind == reflect.Map {
+		for iter := field.MapRange(); iter.Next(); {
+			validate(fmt.Sprintf("[%v]", iter.Key()), iter.Value())
+		}
+	} else {
+		for i, l := 0, field.Len(); i < l; i++ {
+			validate(fmt.Sprintf("[%d]", i), field.Index(i))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// EnumFor returns an error if the field is not one of the values allowed for the value of another field. The first param
+// is the name of the other field and the second param is the name of a map of sets registered with `RegisterSetMap`.
+//
+// Example
+//  validator.RegisterSetMap("states", map[string][]string{
+//    "US": {"CA", "NY"},
+//    "MX": {"JAL", "NLE"},
+//  })
+//
+//  type Struct struct {
+//    Field  string `json:"field" validate:"enum_for:Field2,states"` // 'field' is not valid for the selected 'field2'
+//    Field2 string `json:"field2"`
+//  }
+//
+func EnumFor(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the enum_for tag must be applied to a string")
+	}
+	if len(ps.Params) != 2 {
+		panic(fmt.Errorf("enum_for requires a field name and a set map name"))
+	}
+	name := unquote(ps.Params[1])
+	setMap, ok := setMaps[name]
+	if !ok {
+		panic(fmt.Errorf("'%s' is not a registered set map", name))
+	}
+	fValue, fName := sibling(ps, ps.Params[0])
+	field := ps.Field.String()
+	for _, value := range setMap[stringify(fValue)] {
+		if field == value {
+			return nil
+		}
+	}
+	return errorf(ps.Tag, message.Key("enum_for", "'%s' is not valid for the selected '%s'"), ps.FieldName, fName)
+}
+
+// ColWidth returns an error if the number of columns the field takes up when displayed is not between the min and max passed in.
+// East Asian wide and fullwidth characters take up two columns and every other character takes up one.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"colwidth:1,40"` // 'field' must be between 1 and 40 columns wide
+//  }
+//
+func ColWidth(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the colwidth tag must be applied to a string")
+	}
+	if len(ps.Params) != 2 {
+		panic(fmt.Errorf("colwidth requires a min and a max"))
+	}
+	params := ps.paramValues()
+	min, err := strconv.Atoi(params[0])
+	if err != nil {
+		panic(fmt.Errorf("colwidth requires a numeric min, got '%s'", params[0]))
+	}
+	max, err := strconv.Atoi(params[1])
+	if err != nil || max < min {
+		panic(fmt.Errorf("colwidth requires a numeric max that is at least the min, got '%s'", params[1]))
+	}
+
+	// count the columns
+	var columns int
+	for _, r := range ps.Field.String() {
+		if kind := width.LookupRune(r).Kind(); kind == width.EastAsianWide || kind == width.EastAsianFullwidth {
+			columns += 2
+		} else {
+			columns++
+		}
+	}
+	if columns >= min && columns <= max {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("colwidth", "'%s' must be between %d and %d columns wide"), ps.FieldName, min, max)
+}
+
+// Enum returns an error if the field is not one of the values of the enum registered with `RegisterEnum` or `RegisterSet` under the name passed in.
+// Fields that are not strings are compared using their text or `String()` representation, so named Go enum types can be validated too.
+//
+// Example
+//  validator.RegisterEnum("role", []string{"admin", "user"})
+//
+//  type Struct struct {
+//    Field  string `json:"field" validate:"enum:role"` // 'field' must be a valid role
+//  }
+//
+func Enum(ps *RuleParams) error {
+	if len(ps.Params) != 1 {
+		panic(fmt.Errorf("enum requires exactly one enum name"))
+	}
+	name := ps.typedParams()[0].Value
+	values, ok := sets[name]
+	if !ok {
+		panic(fmt.Errorf("'%s' is not a registered enum", name))
+	}
+	field := stringify(ps.Field)
+	for _, value := range values {
+		if field == value {
+			return nil
+		}
+	}
+	return errorf(ps.Tag, message.Key("enum", "'%s' must be a valid %s"), ps.FieldName, name)
+}
+
+// EQField returns an error if the field does not equal the value of the field name passed in
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"eqfield:Field2"` // 'field' must match 'field2'
+//    Field2 string `json:"field2"`
+//  }
+//
+func EQField(ps *RuleParams) error {
+	if len(ps.Params) != 1 {
+		panic(fmt.Errorf("eqfield requires exactly one field name"))
+	}
+	fValue, fName := sibling(ps, ps.Params[0])
+	if equal(ps.Field, fValue) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("eqfield", "'%s' must match '%s'"), ps.FieldName, fName)
+}
+
+// NEField returns an error if the field equals the value of the field name passed in
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"nefield:Field2"` // 'field' must be different from 'field2'
+//    Field2 string `json:"field2"`
+//  }
+//
+func NEField(ps *RuleParams) error {
+	if len(ps.Params) != 1 {
+		panic(fmt.Errorf("nefield requires exactly one field name"))
+	}
+	fValue, fName := sibling(ps, ps.Params[0])
+	if !equal(ps.Field, fValue) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("nefield", "'%s' must be different from '%s'"), ps.FieldName, fName)
+}
+
+// Glob returns an error if the field does not match the shell style glob pattern passed in. The syntax of the pattern is the
+// same as `path.Match`, and patterns that contain commas must be quoted.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"glob:'*.png'"` // 'field' does not match the required pattern
+//  }
+//
+func Glob(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the glob tag must be applied to a string")
+	}
+	if len(ps.Params) != 1 {
+		panic(fmt.Errorf("glob requires exactly one pattern"))
+	}
+	pattern := unquote(ps.Params[0])
+	matched, err := path.Match(pattern, ps.Field.String())
+	if err != nil {
+		panic(fmt.Errorf("'%s' is not a valid glob pattern", pattern))
+	} else if matched {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("glob", "'%s' does not match the required pattern"), ps.FieldName)
+}
+
+// Hex returns an error if the field is not a valid hexadecimal string
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"hex"` // 'field' must be valid hex
+//  }
+//
+func Hex(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the hex tag must be applied to a string")
+	}
+	if _, err := hex.DecodeString(ps.Field.String()); err == nil {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("hex", "'%s' must be valid hex"), ps.FieldName)
+}
+
+// Base64 returns an error if the field is not a valid base64 string. The standard encoding is used by default, and the
+// url safe encoding is used when the `url` param is passed in.
+//
+// Example
+//  type Struct struct {
+//    Field   string `json:"field" validate:"base64"`      // 'field' must be valid base64
+//    Field2  string `json:"field2" validate:"base64:url"` // 'field2' must be valid base64
+//  }
+//
+func Base64(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the base64 tag must be applied to a string")
+	}
+	enc := base64.StdEncoding
+	for _, p := range ps.typedParams() {
+		switch p.Value {
+		case "std":
+			enc = base64.StdEncoding
+		case "url":
+			enc = base64.URLEncoding
+		default:
+			panic(fmt.Errorf("'%s' is not a valid param for base64", p.Value))
+		}
+	}
+	if _, err := enc.DecodeString(ps.Field.String()); err == nil {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("base64", "'%s' must be valid base64"), ps.FieldName)
+}
+
+// Base32 returns an error if the field is not a valid base32 string. The standard encoding is used by default, and the
+// extended hex encoding is used when the `hex` param is passed in.
+//
+// Example
+//  type Struct struct {
+//    Field   string `json:"field" validate:"base32"`      // 'field' must be valid base32
+//    Field2  string `json:"field2" validate:"base32:hex"` // 'field2' must be valid base32
+//  }
+//
+func Base32(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the base32 tag must be applied to a string")
+	}
+	enc := base32.StdEncoding
+	for _, p := range ps.typedParams() {
+		switch p.Value {
+		case "std":
+			enc = base32.StdEncoding
+		case "hex":
+			enc = base32.HexEncoding
+		default:
+			panic(fmt.Errorf("'%s' is not a valid param for base32", p.Value))
+		}
+	}
+	if _, err := enc.DecodeString(ps.Field.String()); err == nil {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("base32", "'%s' must be valid base32"), ps.FieldName)
+}
+
+// Base58 returns an error if the field is not a valid base58 string, as used by bitcoin and other crypto currency addresses.
+// The alphabet leaves out the characters that are easy to mistake for each other: 0, O, I and l.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"base58"` // 'field' must be valid base58
+//  }
+//
+func Base58(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the base58 tag must be applied to a string")
+	}
+	if base58.MatchString(ps.Field.String()) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("base58", "'%s' must be valid base58"), ps.FieldName)
+}
+
+// JSON returns an error if the field does not contain valid json
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"json"` // 'field' must be valid json
+//  }
+//
+func JSON(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the json tag must be applied to a string")
+	}
+	if json.Valid([]byte(ps.Field.String())) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("json", "'%s' must be valid json"), ps.FieldName)
+}
+
+// StartsWith returns an error if the field does not start with one of the prefixes passed in.
+// Prefixes that contain characters other than letters, numbers and underscores must be quoted.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"startswith:'https://','http://'"` // 'field' must start with https:// or http://
+//  }
+//
+func StartsWith(ps *RuleParams) error {
+	return affix(ps, "startswith", strings.HasPrefix, message.Key("startswith", `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $affix := .}}{{if eq $i 0}}'{{$affix}}'{{else}}{{if eq $i 1}} must start with {{else if eq $i $last}} or {{else}}, {{end}}{{$affix}}{{end}}{{end}}`))
+}
+
+// EndsWith returns an error if the field does not end with one of the suffixes passed in.
+// Suffixes that contain characters other than letters, numbers and underscores must be quoted.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"endswith:'.com','.org'"` // 'field' must end with .com or .org
+//  }
+//
+func EndsWith(ps *RuleParams) error {
+	return affix(ps, "endswith", strings.HasSuffix, message.Key("endswith", `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $affix := .}}{{if eq $i 0}}'{{$affix}}'{{else}}{{if eq $i 1}} must end with {{else if eq $i $last}} or {{else}}, {{end}}{{$affix}}{{end}}{{end}}`))
+}
+
+// Lowercase returns an error if the field contains any uppercase letters
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"lowercase"` // 'field' must be lowercase
+//  }
+//
+func Lowercase(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the lowercase tag must be applied to a string")
+	}
+	if field := ps.Field.String(); field == strings.ToLower(field) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("lowercase", "'%s' must be lowercase"), ps.FieldName)
+}
+
+// Uppercase returns an error if the field contains any lowercase letters
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"uppercase"` // 'field' must be uppercase
+//  }
+//
+func Uppercase(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the uppercase tag must be applied to a string")
+	}
+	if field := ps.Field.String(); field == strings.ToUpper(field) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("uppercase", "'%s' must be uppercase"), ps.FieldName)
+}
+
+// NotBlank returns an error if the field is a string that is empty or only contains white space.
+// Fields of every other kind must be set, just like `required`.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"notblank"` // 'field' must not be blank
+//  }
+//
+func NotBlank(ps *RuleParams) error {
+	if ps.Field.Kind() == reflect.String && len(strings.TrimSpace(ps.Field.String())) > 0 {
+		return nil
+	} else if ps.Field.Kind() != reflect.String && ps.hasValue(ps.Field) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("notblank", "'%s' must not be blank"), ps.FieldName)
+}
+
+// StrongPassword returns an error if the field doesn't contain a strong password. The params are the minimum length, and the
+// minimum number of uppercase letters, lowercase letters, digits and symbols it must contain, which default to 8, 1, 1, 1 and 1.
+// Only the first requirement the password doesn't meet is reported.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"strongpassword:12,1,1,2"` // 'field' must be at least 12 characters long
+//  }
+//
+func StrongPassword(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the strongpassword tag must be applied to a string")
+	}
+	mins := []int{8, 1, 1, 1, 1}
+	if len(ps.Params) > len(mins) {
+		panic(fmt.Errorf("strongpassword accepts at most %d params", len(mins)))
+	}
+	for i, p := range ps.paramValues() {
+		min, err := strconv.Atoi(p)
+		if err != nil || min < 0 {
+			panic(fmt.Errorf("strongpassword requires numeric params, got '%s'", p))
+		}
+		mins[i] = min
+	}
+
+	// count the characters of each kind
+	var length, upper, lower, digits, symbols int
+	for _, r := range ps.Field.String() {
+		length++
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbols++
+		}
+	}
+
+	// report the first unmet requirement
+	switch {
+	case length < mins[0]:
+		return errorf(ps.Tag, message.Key("strongpassword.length", "'%s' must be at least %d characters long"), ps.FieldName, mins[0])
+	case upper < mins[1]:
+		return errorf(ps.Tag, message.Key("strongpassword.upper", "'%s' must contain %d or more uppercase letters"), ps.FieldName, mins[1])
+	case lower < mins[2]:
+		return errorf(ps.Tag, message.Key("strongpassword.lower", "'%s' must contain %d or more lowercase letters"), ps.FieldName, mins[2])
+	case digits < mins[3]:
+		return errorf(ps.Tag, message.Key("strongpassword.digits", "'%s' must contain %d or more digits"), ps.FieldName, mins[3])
+	case symbols < mins[4]:
+		return errorf(ps.Tag, message.Key("strongpassword.symbols", "'%s' must contain %d or more symbols (eg. @!#)"), ps.FieldName, mins[4])
+	}
+	return nil
+}
+
+// Latitude returns an error if the field is not a number between -90 and 90. Strings are parsed as numbers.
+//
+// Example
+//  type Struct struct {
+//    Field  float64 `json:"field" validate:"latitude"` // 'field' must be a valid latitude
+//  }
+//
+func Latitude(ps *RuleParams) error {
+	if isValid := coordinate(ps, "latitude", 90); isValid {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("latitude", "'%s' must be a valid latitude"), ps.FieldName)
+}
+
+// Longitude returns an error if the field is not a number between -180 and 180. Strings are parsed as numbers.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"longitude"` // 'field' must be a valid longitude
+//  }
+//
+func Longitude(ps *RuleParams) error {
+	if isValid := coordinate(ps, "longitude", 180); isValid {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("longitude", "'%s' must be a valid longitude"), ps.FieldName)
+}
+
+// coordinate implements `Latitude` and `Longitude`
+func coordinate(ps *RuleParams, rule string, limit float64) bool {
+	var f float64
+	switch ps.Field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(ps.Field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = float64(ps.Field.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = ps.Field.Float()
+	case reflect.String:
+		var err error
+		if f, err = strconv.ParseFloat(strings.TrimSpace(ps.Field.String()), 64); err != nil {
+			return false
+		}
+	default:
+		panic(fmt.Errorf("the %s tag must be applied to a number or a string", rule))
+	}
+	return f >= -limit && f <= limit
+}
+
+// HexColor returns an error if the field is not a #RGB, #RRGGBB or #RRGGBBAA hex color
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"hexcolor"` // 'field' must be a valid hex color
+//  }
+//
+func HexColor(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the hexcolor tag must be applied to a string")
+	}
+	if hexColor.MatchString(ps.Field.String()) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("hexcolor", "'%s' must be a valid hex color"), ps.FieldName)
+}
+
+// Currency returns an error if the field is not an ISO 4217 currency code. Codes must be uppercase, unless the `fold` param is passed in.
+//
+// Example
+//  type Struct struct {
+//    Field   string `json:"field" validate:"currency"`       // 'field' must be a valid currency code, e.g. "USD"
+//    Field2  string `json:"field2" validate:"currency:fold"` // 'field2' must be a valid currency code, e.g. "USD" or "usd"
+//  }
+//
+func Currency(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the currency tag must be applied to a string")
+	}
+	var isFolded bool
+	for _, p := range ps.typedParams() {
+		if p.Value != "fold" {
+			panic(fmt.Errorf("'%s' is not a valid param for currency", p.Value))
+		}
+		isFolded = true
+	}
+	code := ps.Field.String()
+	if isFolded {
+		code = strings.ToUpper(code)
+	}
+	if currencies[code] {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("currency", "'%s' must be a valid currency code"), ps.FieldName)
+}
+
+// CountryCode returns an error if the field is not an ISO 3166 country code. Alpha-2 codes are expected by default, pass in the `alpha3` param to expect alpha-3 codes instead.
+//
+// Example
+//  type Struct struct {
+//    Field   string `json:"field" validate:"country"`         // 'field' must be a valid ISO country code, e.g. "US"
+//    Field2  string `json:"field2" validate:"country:alpha3"` // 'field2' must be a valid ISO country code, e.g. "USA"
+//  }
+//
+func CountryCode(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the country tag must be applied to a string")
+	}
+	codes := countries[0]
+	for _, p := range ps.typedParams() {
+		switch p.Value {
+		case "alpha2":
+			codes = countries[0]
+		case "alpha3":
+			codes = countries[1]
+		default:
+			panic(fmt.Errorf("'%s' is not a valid param for country", p.Value))
+		}
+	}
+	if codes[ps.Field.String()] {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("country", "'%s' must be a valid ISO country code"), ps.FieldName)
+}
+
+// SemVer returns an error if the field is not a semantic version, e.g. "1.2.3", "1.2.3-beta.1" or "1.2.3+build.5".
+// Constraints can be passed in as params to limit the versions allowed, e.g. '>=1.2.0','<2.0.0'. A constraint is made up
+// of one of the `=`, `!=`, `>`, `>=`, `<` or `<=` operators and a version. Versions without an operator must be equal.
+//
+// Example
+//  type Struct struct {
+//    Field   string `json:"field" validate:"semver"`                      // 'field' must be a valid semantic version
+//    Field2  string `json:"field2" validate:"semver:'>=1.2.0','<2.0.0'"` // 'field2' must be a version >=1.2.0
+//  }
+//
+func SemVer(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the semver tag must be applied to a string")
+	}
+
+	// parse the constraints
+	type constraint struct {
+		operator, version, raw string
+	}
+	constraints := make([]constraint, len(ps.Params))
+	for i, p := range ps.Params {
+		raw := unquote(p)
+		c := constraint{raw: raw, operator: "="}
+		for _, operator := range []string{">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(raw, operator) {
+				c.operator = operator
+				break
+			}
+		}
+		c.version = strings.TrimSpace(strings.TrimPrefix(raw, c.operator))
+		if !semVer.MatchString(c.version) {
+			panic(fmt.Errorf("'%s' is not a valid constraint for semver", raw))
+		}
+		constraints[i] = c
+	}
+
+	// check the version against each of the constraints
+	version := ps.Field.String()
+	if !semVer.MatchString(version) {
+		return errorf(ps.Tag, message.Key("semver", "'%s' must be a valid semantic version"), ps.FieldName)
+	}
+	for _, c := range constraints {
+		cmp := compareSemVer(version, c.version)
+		var ok bool
+		switch c.operator {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return errorf(ps.Tag, message.Key("semver.constraint", "'%s' must be a version %s"), ps.FieldName, c.raw)
+		}
+	}
+	return nil
+}
+
+// compareSemVer returns -1, 0 or 1 if the semantic version a has a lower, equal or higher precedence than b
+func compareSemVer(a, b string) int {
+	// build metadata doesn't affect precedence
+	a, b = strings.SplitN(a, "+", 2)[0], strings.SplitN(b, "+", 2)[0]
+	aVersion, aPre := splitPreRelease(a)
+	bVersion, bPre := splitPreRelease(b)
+	aParts, bParts := strings.Split(aVersion, "."), strings.Split(bVersion, ".")
+	for i := range aParts {
+		if cmp := compareNumeric(aParts[i], bParts[i]); cmp != 0 {
+			return cmp
+		}
+	}
+
+	// a version without a pre-release has a higher precedence than one with a pre-release
+	switch {
+	case len(aPre) == 0 && len(bPre) == 0:
+		return 0
+	case len(aPre) == 0:
+		return 1
+	case len(bPre) == 0:
+		return -1
+	}
+
+	// compare each of the pre-release identifiers, numeric identifiers always have a lower precedence than alphanumeric ones
+	aIDs, bIDs := strings.Split(aPre, "."), strings.Split(bPre, ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		aIsNumeric, bIsNumeric := isNumeric(aIDs[i]), isNumeric(bIDs[i])
+		var cmp int
+		switch {
+		case aIsNumeric && bIsNumeric:
+			cmp = compareNumeric(aIDs[i], bIDs[i])
+		case aIsNumeric:
+			cmp = -1
+		case bIsNumeric:
+			cmp = 1
+		default:
+			cmp = strings.Compare(aIDs[i], bIDs[i])
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	switch {
+	case len(aIDs) < len(bIDs):
+		return -1
+	case len(aIDs) > len(bIDs):
+		return 1
+	}
+	return 0
+}
+
+// splitPreRelease splits a semantic version without build metadata into its version and pre-release
+func splitPreRelease(version string) (string, string) {
+	if i := strings.Index(version, "-"); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+// compareNumeric compares two numbers without leading zeros that may be too large to parse
+func compareNumeric(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// isNumeric returns true if the string is made up entirely of digits
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+// Sorted returns an error if the elements of the slice or array field are not in ascending order, or descending order if the
+// `desc` param is passed in. Equal elements next to each other are allowed. The elements must be integers, floats or strings.
+//
+// Example
+//  type Struct struct {
+//    Field   []int    `json:"field" validate:"sorted"`       // 'field' must be sorted, e.g. [1, 2, 2, 3]
+//    Field2  []string `json:"field2" validate:"sorted:desc"` // 'field2' must be sorted, e.g. ["c", "b", "a"]
+//  }
+//
+func Sorted(ps *RuleParams) error {
+	field := ps.Field
+	if kind := field.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		panic("the sorted tag must be applied to a slice or an array")
+	}
+	isDescending := false
+	for _, p := range ps.typedParams() {
+		switch p.Value {
+		case "asc":
+			isDescending = false
+		case "desc":
+			isDescending = true
+		default:
+			panic(fmt.Errorf("'%s' is not a valid param for sorted", p.Value))
+		}
+	}
+
+	// compare the elements by their kind
+	var less func(a, b reflect.Value) bool
+	switch field.Type().Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		less = func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+	case reflect.Float32, reflect.Float64:
+		less = func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+	case reflect.String:
+		less = func(a, b reflect.Value) bool { return a.String() < b.String() }
+	default:
+		panic("the sorted tag must be applied to a slice or an array of integers, floats or strings")
+	}
+	for i, l := 1, field.Len(); i < l; i++ {
+		prev, next := field.Index(i-1), field.Index(i)
+		if isDescending {
+			prev, next = next, prev
+		}
+		if less(next, prev) {
+			return errorf(ps.Tag, message.Key("sorted", "'%s' must be sorted"), ps.FieldName)
+		}
+	}
+	return nil
+}
+
+// Format returns an error if the field is not in the format whose name is passed in as a param. The built in formats are
+// `email`, `e164`, `rfc3339`, `ipv4`, `ipv6`, `json`, `hexcolor` and `semver`, and more can be added with `RegisterFormat`.
+//
+// Example
+//  type Struct struct {
+//    Field   string `json:"field" validate:"format:e164"`     // 'field' is not a valid e164, e.g. "+15551234567"
+//    Field2  string `json:"field2" validate:"format:rfc3339"` // 'field2' is not a valid rfc3339, e.g. "2006-01-02T15:04:05Z"
+//  }
+//
+func Format(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the format tag must be applied to a string")
+	}
+	params := ps.typedParams()
+	if len(params) != 1 {
+		panic(fmt.Errorf("format requires exactly one format name"))
+	}
+	name := params[0].Value
+	isValid, ok := formats[name]
+	if !ok {
+		panic(fmt.Errorf("'%s' is not a valid format", name))
+	}
+	if isValid(ps.Field.String()) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("format", "'%s' is not a valid %s"), ps.FieldName, name)
+}
+
+// RuneLen returns an error if the number of characters in the field is not within the min and max passed in. Unlike `len`,
+// characters are counted as runes, so multibyte characters such as "é" or emoji count as one character. The max can be
+// left out.
+//
+// Example
+//  type Struct struct {
+//    Field   string `json:"field" validate:"runelen:1,10"` // 'field' must be 1 to 10 characters
+//    Field2  string `json:"field2" validate:"runelen:5"`    // 'field2' must be 5 characters or more
+//  }
+//
+func RuneLen(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the runelen tag must be applied to a string")
+	}
+	params := ps.paramValues()
+	if len(params) == 0 || len(params) > 2 {
+		panic(fmt.Errorf("runelen requires a min and an optional max"))
+	}
+	var bounds []int
+	for _, p := range params {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			panic(fmt.Errorf("'%s' is not a valid length for runelen", p))
+		}
+		bounds = append(bounds, n)
+	}
+	min, isMaxSet := bounds[0], len(bounds) > 1
+	if l := utf8.RuneCountInString(ps.Field.String()); l >= min && (!isMaxSet || l <= bounds[1]) {
+		return nil
+	} else if isMaxSet {
+		return errorf(ps.Tag, message.Key("runelen", "'%s' must be %d to %d characters"), ps.FieldName, min, bounds[1])
+	}
+	return errorf(ps.Tag, message.Key("runelen.min", "'%s' must be %d characters or more"), ps.FieldName, min)
+}
+
+// InList returns an error if the field is not one of the values of the list whose name is passed in as a param.
+// Lists are registered with `RegisterList`.
+//
+// Example
+//  validator.RegisterList("usernames", strings.NewReader("mark\njane"))
+//
+//  type Struct struct {
+//    Field  string `json:"field" validate:"inlist:usernames"` // 'field' is not allowed
+//  }
+//
+func InList(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the inlist tag must be applied to a string")
+	}
+	params := ps.typedParams()
+	if len(params) != 1 {
+		panic(fmt.Errorf("inlist requires exactly one list name"))
+	}
+	name := params[0].Value
+	l, ok := lists[name]
+	if !ok {
+		panic(fmt.Errorf("'%s' is not a registered list", name))
+	}
+	if ok, err := l.contains(ps.Field.String()); err != nil {
+		panic(fmt.Errorf("the '%s' list could not be read: %s", name, err))
+	} else if ok {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("inlist", "'%s' is not allowed"), ps.FieldName)
+}
+
+// LT returns an error if the number or time.Time field is not less than the param passed in. The param can be a
+// reference to another field, e.g. `lt:$Max`. Times that are written in the tag must be quoted RFC3339 times.
+//
+// Example
+//  type Struct struct {
+//    Field   int `json:"field" validate:"lt:10"`      // 'field' must be less than 10
+//    Field2  int `json:"field2" validate:"lt:$Max"`   // 'field2' must be less than 'max'
+//    Max     int `json:"max"`
+//  }
+//
+func LT(ps *RuleParams) error {
+	if cmp, bound := compare(ps, "lt"); cmp >= 0 {
+		return errorf(ps.Tag, message.Key("lt", "'%s' must be less than %s"), ps.FieldName, bound)
+	}
+	return nil
+}
+
+// LTE returns an error if the number or time.Time field is greater than the param passed in. The param can be a
+// reference to another field, e.g. `lte:$Max`. Times that are written in the tag must be quoted RFC3339 times.
+//
+// Example
+//  type Struct struct {
+//    Field   int `json:"field" validate:"lte:10"`     // 'field' must be 10 or less
+//    Field2  int `json:"field2" validate:"lte:$Max"`  // 'field2' must be 'max' or less
+//    Max     int `json:"max"`
+//  }
+//
+func LTE(ps *RuleParams) error {
+	if cmp, bound := compare(ps, "lte"); cmp > 0 {
+		return errorf(ps.Tag, message.Key("lte", "'%s' must be %s or less"), ps.FieldName, bound)
+	}
+	return nil
+}
+
+// GT returns an error if the number or time.Time field is not greater than the param passed in. The param can be a
+// reference to another field, e.g. `gt:$Min`. Times that are written in the tag must be quoted RFC3339 times.
+//
+// Example
+//  type Struct struct {
+//    Field   time.Time `json:"field" validate:"gt:'2020-01-01T00:00:00Z'"` // 'field' must be greater than 2020-01-01T00:00:00Z
+//    Field2  time.Time `json:"field2" validate:"gt:$Start"`               // 'field2' must be greater than 'start'
+//    Start   time.Time `json:"start"`
+//  }
+//
+func GT(ps *RuleParams) error {
+	if cmp, bound := compare(ps, "gt"); cmp <= 0 {
+		return errorf(ps.Tag, message.Key("gt", "'%s' must be greater than %s"), ps.FieldName, bound)
+	}
+	return nil
+}
+
+// GTE returns an error if the number or time.Time field is less than the param passed in. The param can be a
+// reference to another field, e.g. `gte:$Min`. Times that are written in the tag must be quoted RFC3339 times.
+//
+// Example
+//  type Struct struct {
+//    Field   float64 `json:"field" validate:"gte:0.5"`   // 'field' must be 0.5 or more
+//    Field2  float64 `json:"field2" validate:"gte:$Min"` // 'field2' must be 'min' or more
+//    Min     float64 `json:"min"`
+//  }
+//
+func GTE(ps *RuleParams) error {
+	if cmp, bound := compare(ps, "gte"); cmp < 0 {
+		return errorf(ps.Tag, message.Key("gte", "'%s' must be %s or more"), ps.FieldName, bound)
+	}
+	return nil
+}
+
+// LTField returns an error if the number or time.Time field is not less than the field whose name is passed in. It is the
+// same as `lt:$Field`.
+//
+// Example
+//  type Struct struct {
+//    Field    float64 `json:"field" validate:"ltfield:Subtotal"` // 'field' must be less than 'subtotal'
+//    Subtotal float64 `json:"subtotal"`
+//  }
+//
+func LTField(ps *RuleParams) error {
+	if cmp, bound := compare(fieldParams(ps, "ltfield"), "ltfield"); cmp >= 0 {
+		return errorf(ps.Tag, message.Key("lt", "'%s' must be less than %s"), ps.FieldName, bound)
+	}
+	return nil
+}
+
+// LTEField returns an error if the number or time.Time field is greater than the field whose name is passed in. It is the
+// same as `lte:$Field`.
+//
+// Example
+//  type Struct struct {
+//    Field    float64 `json:"field" validate:"ltefield:Subtotal"` // 'field' must be 'subtotal' or less
+//    Subtotal float64 `json:"subtotal"`
+//  }
+//
+func LTEField(ps *RuleParams) error {
+	if cmp, bound := compare(fieldParams(ps, "ltefield"), "ltefield"); cmp > 0 {
+		return errorf(ps.Tag, message.Key("lte", "'%s' must be %s or less"), ps.FieldName, bound)
+	}
+	return nil
+}
+
+// GTField returns an error if the number or time.Time field is not greater than the field whose name is passed in. It is the
+// same as `gt:$Field`.
+//
+// Example
+//  type Struct struct {
+//    Field    float64 `json:"field" validate:"gtfield:Subtotal"` // 'field' must be greater than 'subtotal'
+//    Subtotal float64 `json:"subtotal"`
+//  }
+//
+func GTField(ps *RuleParams) error {
+	if cmp, bound := compare(fieldParams(ps, "gtfield"), "gtfield"); cmp <= 0 {
+		return errorf(ps.Tag, message.Key("gt", "'%s' must be greater than %s"), ps.FieldName, bound)
+	}
+	return nil
+}
+
+// GTEField returns an error if the number or time.Time field is less than the field whose name is passed in. It is the
+// same as `gte:$Field`.
+//
+// Example
+//  type Struct struct {
+//    Field    float64 `json:"field" validate:"gtefield:Subtotal"` // 'field' must be 'subtotal' or more
+//    Subtotal float64 `json:"subtotal"`
+//  }
+//
+func GTEField(ps *RuleParams) error {
+	if cmp, bound := compare(fieldParams(ps, "gtefield"), "gtefield"); cmp < 0 {
+		return errorf(ps.Tag, message.Key("gte", "'%s' must be %s or more"), ps.FieldName, bound)
+	}
+	return nil
+}
+
+// fieldParams returns a copy of the params of rules like `ltfield` with the field name passed in replaced by a reference to the field
+func fieldParams(ps *RuleParams, rule string) *RuleParams {
+	params := ps.typedParams()
+	if len(params) != 1 {
+		panic(fmt.Errorf("%s requires exactly one field name", rule))
+	}
+	p := params[0]
+	switch p.Kind {
+	case ParamReference, ParamIdentifier, ParamString:
+	default:
+		panic(fmt.Errorf("'%s' is not a valid field name for %s", p.Value, rule))
+	}
+	refs := *ps
+	refs.Params = []string{"$" + p.Value}
+	refs.TypedParams = []Param{{Kind: ParamReference, Value: p.Value}}
+	return &refs
+}
+
+// compare implements `LT`, `LTE`, `GT`, `GTE` and the rules that compare fields to their siblings, like `LTField`. It returns -1, 0 or 1 if the field is less than, equal to or greater than
+// the param, and the text that the param should be referred to by in the error message
+func compare(ps *RuleParams, rule string) (int, string) {
+	field := ps.Field
+	isTime := field.Type() == timeType
+	if _, isNumber := number(field); !isNumber && !isTime {
+		panic(fmt.Errorf("the %s tag must be applied to a number or a time", rule))
+	}
+	params := ps.typedParams()
+	if len(params) != 1 {
+		panic(fmt.Errorf("%s requires exactly one parameter", rule))
+	}
+
+	// read the bound from another field or from the tag
+	var bound reflect.Value
+	p, text := params[0], params[0].Value
+	if p.Kind == ParamReference {
+		var fName string
+		bound, fName = ps.reference(p.Value)
+		text = "'" + fName + "'"
+		if _, isNumber := number(bound); (isTime && bound.Type() != timeType) || (!isTime && !isNumber) {
+			panic(fmt.Errorf("'%s' can't be compared to '%s'", p.Value, ps.FieldName))
+		}
+	} else if isTime {
+		t, err := time.Parse(time.RFC3339, p.Value)
+		if err != nil {
+			panic(fmt.Errorf("'%s' is not a valid RFC3339 time for %s", p.Value, rule))
+		}
+		bound = reflect.ValueOf(t)
+	} else if p.Kind == ParamNumber {
+		bound = reflect.ValueOf(p.Number)
+		if i, err := strconv.ParseInt(p.Value, 0, 64); err == nil {
+			bound = reflect.ValueOf(i)
+		}
+	} else {
+		panic(fmt.Errorf("'%s' is not a valid number for %s", p.Value, rule))
+	}
+
+	// compare times, integers, and then any other numbers as floats
+	if isTime {
+		a, b := field.Interface().(time.Time), bound.Interface().(time.Time)
+		switch {
+		case a.Before(b):
+			return -1, text
+		case a.After(b):
+			return 1, text
+		}
+		return 0, text
+	}
+	if c, ok := compareIntegers(field, bound); ok {
+		return c, text
+	}
+	a, _ := number(field)
+	b, _ := number(bound)
+	switch {
+	case a < b:
+		return -1, text
+	case a > b:
+		return 1, text
+	}
+	return 0, text
+}
+
+// number returns the value of an int, uint or float field as a float64
+func number(field reflect.Value) (float64, bool) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), true
+	}
+	return 0, false
+}
+
+// Normalized returns an error if the field is not in a Unicode normalization form. The form is NFC by default, and can be
+// changed by passing in `nfc`, `nfd`, `nfkc` or `nfkd` as a param. Normalizing input before it is compared or stored prevents
+// strings that look the same, like a composed and a decomposed "é", from being treated as different values.
+//
+// Example
+//  type Struct struct {
+//    Field   string `json:"field" validate:"normalized"`       // 'field' must be in normalized form
+//    Field2  string `json:"field2" validate:"normalized:nfkc"` // 'field2' must be in normalized form
+//  }
+//
+func Normalized(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the normalized tag must be applied to a string")
+	}
+	form := norm.NFC
+	for _, p := range ps.Params {
+		switch strings.ToLower(unquote(p)) {
+		case "nfc":
+			form = norm.NFC
+		case "nfd":
+			form = norm.NFD
+		case "nfkc":
+			form = norm.NFKC
+		case "nfkd":
+			form = norm.NFKD
+		default:
+			panic(fmt.Errorf("'%s' is not a valid normalization form", p))
+		}
+	}
+	if form.IsNormalString(ps.Field.String()) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("normalized", "'%s' must be in normalized form"), ps.FieldName)
+}
+
+// Duration returns an error if the string field is not a duration that `time.ParseDuration` understands, e.g. "1h30m".
+// It can also be applied to time.Duration fields, where zero is treated as unset so that a default can be used in its place.
+// A min and an optional max duration can be passed in as params.
+//
+// Example
+//  type Struct struct {
+//    Field   string        `json:"field" validate:"duration"`         // 'field' must be a valid duration
+//    Field2  string        `json:"field2" validate:"duration:1s,1h"`  // 'field2' must be between 1s and 1h
+//    Field3  time.Duration `json:"field3" validate:"duration:100ms"`  // 'field3' must be at least 100ms
+//  }
+//
+func Duration(ps *RuleParams) error {
+	// parse the min and max
+	params := ps.paramValues()
+	if len(params) > 2 {
+		panic(fmt.Errorf("duration takes a min and an optional max"))
+	}
+	bounds := make([]time.Duration, len(params))
+	for i, p := range params {
+		var err error
+		if bounds[i], err = time.ParseDuration(p); err != nil {
+			panic(fmt.Errorf("'%s' is not a valid duration for duration", p))
+		}
+	}
+
+	// parse the field
+	var d time.Duration
+	switch field := ps.Field; {
+	case field.Type() == durationType:
+		if d = time.Duration(field.Int()); d == 0 {
+			return nil
+		}
+	case field.Kind() == reflect.String:
+		var err error
+		if d, err = time.ParseDuration(field.String()); err != nil {
+			return errorf(ps.Tag, message.Key("duration", "'%s' must be a valid duration"), ps.FieldName)
+		}
+	default:
+		panic("the duration tag must be applied to a string or a time.Duration")
+	}
+	if len(bounds) == 2 && (d < bounds[0] || d > bounds[1]) {
+		return errorf(ps.Tag, message.Key("duration.range", "'%s' must be between %s and %s"), ps.FieldName, params[0], params[1])
+	} else if len(bounds) == 1 && d < bounds[0] {
+		return errorf(ps.Tag, message.Key("duration.min", "'%s' must be at least %s"), ps.FieldName, params[0])
+	}
+	return nil
+}
+
+// Ext returns an error if the field is not a file name or path that ends with one of the extensions passed in.
+// Extensions are compared case insensitively, and can be written with or without their leading dot.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"ext:.jpg,.png,.gif"` // 'field' must have extension .jpg, .png or .gif
+//    Field2 string `json:"field2" validate:"ext:tar.gz"`        // 'field2' must have extension tar.gz
+//  }
+//
+func Ext(ps *RuleParams) error {
+	return affix(ps, "ext", hasExt, message.Key("ext", `{{$len := len .}}{{$last := minus $len 1}}{{range $i, $affix := .}}{{if eq $i 0}}'{{$affix}}'{{else}}{{if eq $i 1}} must have extension {{else if eq $i $last}} or {{else}}, {{end}}{{$affix}}{{end}}{{end}}`))
+}
+
+// hasExt returns true if the path ends with the extension, ignoring case
+func hasExt(path, ext string) bool {
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return len(path) > len(ext) && strings.EqualFold(path[len(path)-len(ext):], ext)
+}
+
+// IBAN returns an error if the field is not an International Bank Account Number with the length of its country and a valid mod-97 checksum.
+// Spaces are ignored, e.g. "GB82 WEST 1234 5698 7654 32".
+//
+// Example
+//  type Struct struct {
+//    Field string `json:"field" validate:"iban"` // 'field' must be a valid IBAN
+//  }
+//
+func IBAN(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the iban tag must be applied to a string")
+	}
+	if isIBAN(strings.ReplaceAll(ps.Field.String(), " ", "")) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("iban", "'%s' must be a valid IBAN"), ps.FieldName)
+}
+
+// isIBAN returns true if the iban has the length of its country, and its remainder is 1 when it is divided by 97
+// after moving the country code and check digits to the end, and replacing its letters with numbers (A = 10, ..., Z = 35)
+func isIBAN(iban string) bool {
+	if len(iban) < 4 || ibanLengths[iban[:2]] != len(iban) || !isNumeric(iban[2:4]) {
+		return false
+	}
+	var remainder int
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			remainder = (remainder*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			remainder = (remainder*100 + int(r-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// MultipleOf returns an error if the number is not a multiple of the number passed in as a param. Floats are allowed
+// a small tolerance for rounding errors, so 0.3 is a multiple of 0.1.
+//
+// Example
+//  type Struct struct {
+//    Field  int     `json:"field" validate:"multipleof:5"`     // 'field' must be a multiple of 5
+//    Field2 float64 `json:"field2" validate:"multipleof:0.25"` // 'field2' must be a multiple of 0.25
+//  }
+//
+func MultipleOf(ps *RuleParams) error {
+	params := ps.typedParams()
+	if len(params) != 1 || params[0].Kind != ParamNumber || params[0].Number == 0 {
+		panic(fmt.Errorf("multipleof requires one number other than 0"))
+	}
+	f, ok := number(ps.Field)
+	if !ok {
+		panic("the multipleof tag must be applied to a number")
+	}
+	divisor := params[0].Number
+	if m, ok := magnitude(ps.Field); ok && divisor == math.Trunc(divisor) && math.Abs(divisor) < 1<<63 {
+		if m%uint64(math.Abs(divisor)) == 0 {
+			return nil
+		}
+	} else if q := f / divisor; math.Abs(q-math.Round(q)) < 1e-9 {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("multipleof", "'%s' must be a multiple of %s"), ps.FieldName, params[0].Value)
+}
+
+// Before returns an error if the time is not before the param, which can be `now`, a date, an RFC3339 time or a
+// reference to another field. Strings are read as RFC3339 times.
+//
+// Example
+//  type Struct struct {
+//    Field  time.Time `json:"field" validate:"before:now"`         // 'field' must be in the past
+//    Field2 string    `json:"field2" validate:"before:2030-01-01"` // 'field2' must be before 2030-01-01
+//    Field3 time.Time `json:"field3" validate:"before:$Field"`     // 'field3' must be before 'field'
+//  }
+//
+func Before(ps *RuleParams) error {
+	field, bound, text, ok := window(ps, "before")
+	if ok && field.Before(bound) {
+		return nil
+	} else if text == "now" {
+		return errorf(ps.Tag, message.Key("before.now", "'%s' must be in the past"), ps.FieldName)
+	}
+	return errorf(ps.Tag, message.Key("before", "'%s' must be before %s"), ps.FieldName, text)
+}
+
+// After returns an error if the time is not after the param, which can be `now`, a date, an RFC3339 time or a
+// reference to another field. Strings are read as RFC3339 times.
+//
+// Example
+//  type Struct struct {
+//    Field  time.Time `json:"field" validate:"after:now"`         // 'field' must be in the future
+//    Field2 string    `json:"field2" validate:"after:2030-01-01"` // 'field2' must be after 2030-01-01
+//    Field3 time.Time `json:"field3" validate:"after:$Field"`     // 'field3' must be after 'field'
+//  }
+//
+func After(ps *RuleParams) error {
+	field, bound, text, ok := window(ps, "after")
+	if ok && field.After(bound) {
+		return nil
+	} else if text == "now" {
+		return errorf(ps.Tag, message.Key("after.now", "'%s' must be in the future"), ps.FieldName)
+	}
+	return errorf(ps.Tag, message.Key("after", "'%s' must be after %s"), ps.FieldName, text)
+}
+
+// window implements `Before` and `After`. It returns the time of the field, the time of the param and the text of the param
+// for error messages. It isn't ok if the field is a string that isn't an RFC3339 time
+func window(ps *RuleParams, rule string) (time.Time, time.Time, string, bool) {
+	if ps.Field.Kind() != reflect.String && ps.Field.Type() != timeType {
+		panic(fmt.Errorf("the %s tag must be applied to a time or a string", rule))
+	}
+	params := ps.typedParams()
+	if len(params) != 1 {
+		panic(fmt.Errorf("%s requires exactly one parameter", rule))
+	}
+
+	// read the bound from the clock, another field or the tag
+	var bound time.Time
+	p, text := params[0], params[0].Value
+	if p.Kind == ParamIdentifier && p.Value == "now" {
+		bound = ps.now()
+	} else if p.Kind == ParamReference {
+		fValue, fName := ps.reference(p.Value)
+		text = "'" + fName + "'"
+		if fValue.Type() == timeType {
+			bound = fValue.Interface().(time.Time)
+		} else if fValue.Kind() == reflect.String {
+			bound, _ = time.Parse(time.RFC3339, fValue.String())
+		} else {
+			panic(fmt.Errorf("'%s' can't be compared to '%s'", p.Value, ps.FieldName))
+		}
+	} else if t, err := time.Parse("2006-01-02", p.Value); err == nil {
+		bound = t
+	} else if t, err := time.Parse(time.RFC3339, p.Value); err == nil {
+		bound = t
+	} else {
+		panic(fmt.Errorf("'%s' is not a valid date or RFC3339 time for %s", p.Value, rule))
+	}
+
+	if ps.Field.Kind() != reflect.String {
+		return ps.Field.Interface().(time.Time), bound, text, true
+	}
+	field, err := time.Parse(time.RFC3339, ps.Field.String())
+	return field, bound, text, err == nil
+}
+
+// ByteSize returns an error if the number of bytes in the string or []byte field is more than the max passed in, or
+// outside of the min and max if both are passed in. Unlike `runelen`, multibyte characters such as "é" count as more than one byte,
+// which makes it suitable for enforcing storage limits.
+//
+// Example
+//  type Struct struct {
+//    Field   string `json:"field" validate:"bytesize:1024"`    // 'field' must be at most 1024 bytes
+//    Field2  []byte `json:"field2" validate:"bytesize:1,255"` // 'field2' must be 1 to 255 bytes
+//  }
+//
+func ByteSize(ps *RuleParams) error {
+	var size int
+	switch field := ps.Field; {
+	case field.Kind() == reflect.String:
+		size = len(field.String())
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8:
+		size = field.Len()
+	default:
+		panic("the bytesize tag must be applied to a string or a []byte")
+	}
+	params := ps.paramValues()
+	if len(params) == 0 || len(params) > 2 {
+		panic(fmt.Errorf("bytesize requires a max or a min and a max"))
+	}
+	var bounds []int
+	for _, p := range params {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			panic(fmt.Errorf("'%s' is not a valid size for bytesize", p))
+		}
+		bounds = append(bounds, n)
+	}
+	min, max := 0, bounds[0]
+	if len(bounds) > 1 {
+		min, max = bounds[0], bounds[1]
+	}
+	if size >= min && size <= max {
+		return nil
+	} else if min == 0 {
+		return errorf(ps.Tag, message.Key("bytesize.max", "'%s' must be at most %s bytes"), ps.FieldName, strconv.Itoa(max))
+	}
+	return errorf(ps.Tag, message.Key("bytesize", "'%s' must be %s to %s bytes"), ps.FieldName, strconv.Itoa(min), strconv.Itoa(max))
+}
+
+// MAC returns an error if the field is not an IEEE 802 MAC address separated by colons, hyphens or dots, e.g.
+// "00:1a:2b:3c:4d:5e", "00-1A-2B-3C-4D-5E" or "001a.2b3c.4d5e"
+//
+// Example
+//  type Struct struct {
+//    Field string `json:"field" validate:"mac"` // 'field' must be a valid MAC address
+//  }
+//
+func MAC(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the mac tag must be applied to a string")
+	}
+	if _, err := net.ParseMAC(ps.Field.String()); err == nil {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("mac", "'%s' must be a valid MAC address"), ps.FieldName)
+}
+
+// Hostname returns an error if the field is not an RFC 1123 hostname: dot separated labels of letters, digits and hyphens
+// that don't start or end with a hyphen, with at most 63 characters in a label and 253 in total. Passing in the `rfc1035` param
+// also requires each label to start with a letter.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"hostname"`          // 'field' must be a valid hostname
+//    Field2 string `json:"field2" validate:"hostname:rfc1035"` // 'field2' must be a valid hostname
+//  }
+//
+func Hostname(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the hostname tag must be applied to a string")
+	}
+	isRFC1035 := false
+	for _, p := range ps.typedParams() {
+		switch p.Value {
+		case "rfc1123":
+			isRFC1035 = false
+		case "rfc1035":
+			isRFC1035 = true
+		default:
+			panic(fmt.Errorf("'%s' is not a valid param for hostname", p.Value))
+		}
+	}
+	if isHostname(ps.Field.String(), isRFC1035) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("hostname", "'%s' must be a valid hostname"), ps.FieldName)
+}
+
+// isHostname returns true if the host is an RFC 1123 hostname, whose labels also start with letters if isRFC1035 is set
+func isHostname(host string, isRFC1035 bool) bool {
+	if len(host) == 0 || len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		} else if isRFC1035 && !('a' <= label[0] && label[0] <= 'z' || 'A' <= label[0] && label[0] <= 'Z') {
+			return false
+		}
+		for _, r := range label {
+			if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Pattern returns an error if the field doesn't match the regular expression registered with `RegisterPattern` under the name passed in
+//
+// Example
+//  validator.RegisterPattern("sku", `^[A-Z]{3}-[0-9]{4}$`)
+//
+//  type Struct struct {
+//    Field  string `json:"field" validate:"pattern:sku"` // 'field' is not in a valid format
+//  }
+//
+func Pattern(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the pattern tag must be applied to a string")
+	}
+	params := ps.typedParams()
+	if len(params) != 1 {
+		panic(fmt.Errorf("pattern requires exactly one pattern name"))
+	}
+	name := params[0].Value
+	pattern, ok := patterns[name]
+	if !ok {
+		panic(fmt.Errorf("'%s' is not a registered pattern", name))
+	}
+	if pattern.MatchString(ps.Field.String()) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("pattern", "'%s' is not in a valid format"), ps.FieldName)
+}
+
+// NonEmpty returns an error if the field is a slice, map or array without any elements. Unlike `required`, which only checks
+// that a slice or map isn't nil, it also fails on empty slices and maps that have been allocated.
+//
+// Example
+//  type Struct struct {
+//    Field  []string          `json:"field" validate:"nonempty"`  // 'field' must not be empty
+//    Field2 map[string]string `json:"field2" validate:"nonempty"` // 'field2' must not be empty
+//  }
+//
+func NonEmpty(ps *RuleParams) error {
+	field := ps.Field
+	for field.Kind() == reflect.Ptr && !field.IsNil() {
+		field = field.Elem()
+	}
+	switch field.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		if field.Len() > 0 {
+			return nil
+		}
+	case reflect.Ptr:
+	default:
+		panic("the nonempty tag must be applied to a slice, map or array")
+	}
+	return errorf(ps.Tag, message.Key("nonempty", "'%s' must not be empty"), ps.FieldName)
+}
+
+// Printable returns an error if the field contains control characters or other characters that can't be printed, like a
+// null byte or a newline, to guard against control sequences being injected into logs and terminals
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"printable"` // 'field' must not contain control characters
+//  }
+//
+func Printable(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the printable tag must be applied to a string")
+	}
+	for _, r := range ps.Field.String() {
+		if unicode.IsControl(r) || !unicode.IsPrint(r) {
+			return errorf(ps.Tag, message.Key("printable", "'%s' must not contain control characters"), ps.FieldName)
+		}
+	}
+	return nil
+}
+
+// Slug returns an error if the field is not a url slug: lowercase letters and digits separated by single hyphens, that doesn't
+// start or end with a hyphen. Passing in the `underscore` param also allows underscores as separators.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"slug"`             // 'field' must be a valid url slug, e.g. "my-first-post"
+//    Field2 string `json:"field2" validate:"slug:underscore"` // 'field2' must be a valid url slug, e.g. "my_first-post"
+//  }
+//
+func Slug(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the slug tag must be applied to a string")
+	}
+	pattern := slug
+	for _, p := range ps.typedParams() {
+		switch p.Value {
+		case "underscore":
+			pattern = underscoreSlug
+		default:
+			panic(fmt.Errorf("'%s' is not a valid param for slug", p.Value))
+		}
+	}
+	if pattern.MatchString(ps.Field.String()) {
+		return nil
+	}
+	return errorf(ps.Tag, message.Key("slug", "'%s' must be a valid url slug"), ps.FieldName)
+}
+
+// Decimal returns an error if the field is not a decimal number that fits in the precision and scale passed in, like the
+// columns of a database, e.g. `decimal:10,2` allows at most 10 digits, 2 of which are after the decimal point. The scale
+// defaults to 0 if it isn't passed in. Leading zeros don't count towards the precision.
+//
+// Example
+//  type Struct struct {
+//    Field  string `json:"field" validate:"decimal:10,2"` // 'field' must have at most 2 decimal places
+//    Field2 string `json:"field2" validate:"decimal:3"`   // 'field2' must have at most 3 digits before the decimal point
+//  }
+//
+func Decimal(ps *RuleParams) error {
+	if ps.Field.Kind() != reflect.String {
+		panic("the decimal tag must be applied to a string")
+	}
+	params := ps.paramValues()
+	if len(params) == 0 || len(params) > 2 {
+		panic(fmt.Errorf("decimal requires a precision or a precision and a scale"))
+	}
+	precision, err := strconv.Atoi(params[0])
+	if err != nil || precision < 1 {
+		panic(fmt.Errorf("'%s' is not a valid precision for decimal", params[0]))
+	}
+	var scale int
+	if len(params) > 1 {
+		if scale, err = strconv.Atoi(params[1]); err != nil || scale < 0 || scale > precision {
+			panic(fmt.Errorf("'%s' is not a valid scale for decimal", params[1]))
+		}
+	}
+	matches := decimal.FindStringSubmatch(ps.Field.String())
+	if matches == nil {
+		return errorf(ps.Tag, message.Key("decimal", "'%s' must be a decimal number"), ps.FieldName)
+	} else if len(matches[2]) > scale {
+		return errorf(ps.Tag, message.Key("decimal.scale", "'%s' must have at most %s decimal places"), ps.FieldName, strconv.Itoa(scale))
+	} else if len(strings.TrimLeft(matches[1], "0")) > precision-scale {
+		return errorf(ps.Tag, message.Key("decimal.precision", "'%s' must have at most %s digits before the decimal point"), ps.FieldName, strconv.Itoa(precision-scale))
+	}
+	return nil
+}
+
+// affix implements `StartsWith`, `EndsWith` and `Ext`
+func affix(ps *RuleParams, rule string, has func(s, affix string) bool, key message.Reference) error {
+	if ps.Field.Kind() != reflect.String {
+		panic(fmt.Errorf("the %s tag must be applied to a string", rule))
+	}
+	affixes := ps.paramValues()
+	if len(affixes) == 0 {
+		panic(fmt.Errorf("%s requires at least one parameter", rule))
+	}
+	for _, a := range affixes {
+		if has(ps.Field.String(), a) {
+			return nil
+		}
+	}
+	return errorTemplate(ps.Tag, key, append([]string{ps.FieldName}, affixes...))
+}
+
+// parseHexColor parses a #RGB or #RRGGBB color into its red, green and blue components
+func parseHexColor(s string) ([3]uint8, bool) {
+	var rgb [3]uint8
+	if len(s) == 0 || s[0] != '#' {
+		return rgb, false
+	}
+	s = s[1:]
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return rgb, false
+	}
+	for i := range rgb {
+		c, err := strconv.ParseUint(s[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return rgb, false
+		}
+		rgb[i] = uint8(c)
+	}
+	return rgb, true
+}
+
+// contrastRatio returns the WCAG contrast ratio between two colors
+func contrastRatio(a, b [3]uint8) float64 {
+	la, lb := luminance(a), luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// luminance returns the WCAG relative luminance of a color
+func luminance(rgb [3]uint8) float64 {
+	var cs [3]float64
+	for i, c := range rgb {
+		cs[i] = float64(c) / 255
+		if cs[i] <= 0.03928 {
+			cs[i] /= 12.92
+		} else {
+			cs[i] = math.Pow((cs[i]+0.055)/1.055, 2.4)
+		}
+	}
+	return 0.2126*cs[0] + 0.7152*cs[1] + 0.0722*cs[2]
+}
+
+var (
+	// timeType is the reflect.Type of time.Time
+	timeType = reflect.TypeOf(time.Time{})
+
+	// durationType is the reflect.Type of time.Duration
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	// emailAddress matches an email address
+	emailAddress = regexp.MustCompile(`^(([^<>()[\]\\.,;:\s@"]+(\.[^<>()[\]\\.,;:\s@"]+)*)|(".+"))@((\[[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}])|(([a-zA-Z\-0-9]+\.)+[a-zA-Z]{2,}))$`)
+
+	// e164 matches an E.164 phone number, e.g. +15551234567
+	e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+	// dockerPath matches the slash separated lowercase path components of a docker image name
+	dockerPath = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+
+	// dockerReference matches a docker image reference and captures its domain, path, tag and digest
+	dockerReference = regexp.MustCompile(`^(?:((?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?)/)?` +
+		`([a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*)` +
+		`(?::([\w][\w.-]{0,127}))?` +
+		`(?:@([A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}))?$`)
+
+	// base58 matches a string in the bitcoin base58 alphabet
+	base58 = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]*$`)
+
+	// slug matches a url slug of lowercase letters and digits separated by hyphens
+	slug = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+	// underscoreSlug matches a url slug of lowercase letters and digits separated by hyphens or underscores
+	underscoreSlug = regexp.MustCompile(`^[a-z0-9]+(?:[-_][a-z0-9]+)*$`)
+
+	// decimal matches a signed decimal number and captures its integer and fractional digits
+	decimal = regexp.MustCompile(`^[+-]?([0-9]+)(?:\.([0-9]+))?$`)
+
+	// hexColor matches a #RGB, #RRGGBB or #RRGGBBAA hex color
+	hexColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+	// semVer matches a semantic version, see https://semver.org
+	semVer = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+	// currencies are the ISO 4217 currency codes
+	currencies = func() map[string]bool {
+		codes := make(map[string]bool)
+		for _, code := range strings.Fields(`
+		AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF
+		BMD BND BOB BOV BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CLF
+		CLP CNY COP COU CRC CUC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB
+		EUR FJD FKP GBP GEL GHS GIP GMD GNF GTQ GYD HKD HNL HTG HUF IDR
+		ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD
+		KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR
+		MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK
+		PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP
+		SLE SLL SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD
+		TWD TZS UAH UGX USD USN UYI UYU UYW UZS VED VES VND VUV WST XAF
+		XAG XAU XBA XBB XBC XBD XCD XCG XDR XOF XPD XPF XPT XSU XUA YER
+		ZAR ZMW ZWG ZWL`) {
+			codes[code] = true
+		}
+		return codes
+	}()
+
+	// countries are the ISO 3166 alpha-2 and alpha-3 country codes
+	countries = func() [2]map[string]bool {
+		codes := [2]map[string]bool{make(map[string]bool), make(map[string]bool)}
+		for i, code := range strings.Fields(`
+		AF AFG  AX ALA  AL ALB  DZ DZA  AS ASM  AD AND  AO AGO  AI AIA
+		AQ ATA  AG ATG  AR ARG  AM ARM  AW ABW  AU AUS  AT AUT  AZ AZE
+		BS BHS  BH BHR  BD BGD  BB BRB  BY BLR  BE BEL  BZ BLZ  BJ BEN
+		BM BMU  BT BTN  BO BOL  BQ BES  BA BIH  BW BWA  BV BVT  BR BRA
+		IO IOT  BN BRN  BG BGR  BF BFA  BI BDI  CV CPV  KH KHM  CM CMR
+		CA CAN  KY CYM  CF CAF  TD TCD  CL CHL  CN CHN  CX CXR  CC CCK
+		CO COL  KM COM  CG COG  CD COD  CK COK  CR CRI  CI CIV  HR HRV
+		CU CUB  CW CUW  CY CYP  CZ CZE  DK DNK  DJ DJI  DM DMA  DO DOM
+		EC ECU  EG EGY  SV SLV  GQ GNQ  ER ERI  EE EST  SZ SWZ  ET ETH
+		FK FLK  FO FRO  FJ FJI  FI FIN  FR FRA  GF GUF  PF PYF  TF ATF
+		GA GAB  GM GMB  GE GEO  DE DEU  GH GHA  GI GIB  GR GRC  GL GRL
+		GD GRD  GP GLP  GU GUM  GT GTM  GG GGY  GN GIN  GW GNB  GY GUY
+		HT HTI  HM HMD  VA VAT  HN HND  HK HKG  HU HUN  IS ISL  IN IND
+		ID IDN  IR IRN  IQ IRQ  IE IRL  IM IMN  IL ISR  IT ITA  JM JAM
+		JP JPN  JE JEY  JO JOR  KZ KAZ  KE KEN  KI KIR  KP PRK  KR KOR
+		KW KWT  KG KGZ  LA LAO  LV LVA  LB LBN  LS LSO  LR LBR  LY LBY
+		LI LIE  LT LTU  LU LUX  MO MAC  MG MDG  MW MWI  MY MYS  MV MDV
+		ML MLI  MT MLT  MH MHL  MQ MTQ  MR MRT  MU MUS  YT MYT  MX MEX
+		FM FSM  MD MDA  MC MCO  MN MNG  ME MNE  MS MSR  MA MAR  MZ MOZ
+		MM MMR  NA NAM  NR NRU  NP NPL  NL NLD  NC NCL  NZ NZL  NI NIC
+		NE NER  NG NGA  NU NIU  NF NFK  MK MKD  MP MNP  NO NOR  OM OMN
+		PK PAK  PW PLW  PS PSE  PA PAN  PG PNG  PY PRY  PE PER  PH PHL
+		PN PCN  PL POL  PT PRT  PR PRI  QA QAT  RE REU  RO ROU  RU RUS
+		RW RWA  BL BLM  SH SHN  KN KNA  LC LCA  MF MAF  PM SPM  VC VCT
+		WS WSM  SM SMR  ST STP  SA SAU  SN SEN  RS SRB  SC SYC  SL SLE
+		SG SGP  SX SXM  SK SVK  SI SVN  SB SLB  SO SOM  ZA ZAF  GS SGS
+		SS SSD  ES ESP  LK LKA  SD SDN  SR SUR  SJ SJM  SE SWE  CH CHE
+		SY SYR  TW TWN  TJ TJK  TZ TZA  TH THA  TL TLS  TG TGO  TK TKL
+		TO TON  TT TTO  TN TUN  TR TUR  TM TKM  TC TCA  TV TUV  UG UGA
+		UA UKR  AE ARE  GB GBR  US USA  UM UMI  UY URY  UZ UZB  VU VUT
+		VE VEN  VN VNM  VG VGB  VI VIR  WF WLF  EH ESH  YE YEM  ZM ZMB
+		ZW ZWE`) {
+			codes[i%2][code] = true
+		}
+		return codes
+	}()
+
+	// ibanLengths are the lengths of the IBANs of each country in the IBAN registry
+	ibanLengths = func() map[string]int {
+		lengths := make(map[string]int)
+		for _, country := range strings.Fields(`
+		AD24 AE23 AL28 AT20 AZ28 BA20 BE16 BG22 BH22 BI27 BR29 BY28 CH21 CR22 CY28 CZ24
+		DE22 DJ27 DK18 DO28 EE20 EG29 ES24 FI18 FK18 FO18 FR27 GB22 GE22 GI23 GL18 GR27
+		GT28 HR21 HU28 IE22 IL23 IQ23 IS26 IT27 JO30 KW30 KZ20 LB28 LC32 LI21 LT20 LU20
+		LV21 LY25 MC27 MD24 ME22 MK19 MN20 MR27 MT31 MU30 NI28 NL18 NO15 OM23 PK24 PL28
+		PS29 PT25 QA29 RO24 RS22 RU33 SA24 SC31 SD18 SE24 SI19 SK24 SM27 SO23 ST25 SV28
+		TL23 TN24 TR26 UA29 VA22 VG24 XK20 YE30`) {
+			lengths[country[:2]], _ = strconv.Atoi(country[2:])
+		}
+		return lengths
+	}()
+)
+
+// sibling returns the value and the name of the field in the parent struct with the name passed in.
+// Fields of nested structs are separated by dots, e.g. `Address.Country`, elements of slices, arrays and maps are
+// in brackets, e.g. `Items[0].Price`, and names that start with `root.` are looked up in the Root instead of the parent,
+// e.g. `root.Currency`
+func sibling(ps *RuleParams, name string) (reflect.Value, string) {
+	parent, parentType, path := ps.Parent, ps.ParentType, name
+	if strings.HasPrefix(name, "root.") {
+		parent, parentType, path = ps.Root, ps.RootType, strings.TrimPrefix(name, "root.")
+	}
+	var fValue reflect.Value
+	var fieldName string
+	for _, segment := range strings.Split(path, ".") {
+		fName, keys := splitKeys(segment)
+		for parent.Kind() == reflect.Ptr || parent.Kind() == reflect.Interface {
+			if parent.Kind() == reflect.Ptr && parent.IsNil() {
+				parent = reflect.Zero(parent.Type().Elem())
+			} else {
+				parent = parent.Elem()
+			}
+			parentType = nil
+		}
+		if parentType == nil && parent.IsValid() {
+			parentType = parent.Type()
+		}
+		var ok bool
+		switch parent.Kind() {
+		case reflect.Struct:
+			var fField reflect.StructField
+			if fField, ok = parentType.FieldByName(fName); ok {
+				fValue = parent.FieldByIndex(fField.Index)
+			}
+			fieldName = ps.validator.fieldName(fField)
+		case reflect.Map:
+			// keys that aren't in the map are the zero value, the same as they would be in a struct
+			if ok = parentType.Key().Kind() == reflect.String; ok {
+				fValue = parent.MapIndex(reflect.ValueOf(fName).Convert(parentType.Key()))
+				if !fValue.IsValid() {
+					fValue = reflect.Zero(parentType.Elem())
+				} else if fValue.Kind() == reflect.Interface && !fValue.IsNil() {
+					fValue = fValue.Elem()
+				}
+				fieldName = fName
+			}
+		}
+		if !ok || !fValue.IsValid() {
+			var parentName string
+			if parentType != nil {
+				parentName = parentType.Name()
+			}
+			panic(fmt.Errorf("'%s.%s' is not a valid field", parentName, fName))
+		}
+		for _, key := range keys {
+			fValue = element(fValue, fName, key)
+			fieldName += "[" + key + "]"
+		}
+		parent, parentType = fValue, nil
+	}
+	return fValue, fieldName
+}
+
+// splitKeys splits the name of a field from the indexes or map keys that follow it, e.g. `Items[0][1]` is split into
+// `Items` and `["0", "1"]`
+func splitKeys(segment string) (string, []string) {
+	i := strings.IndexByte(segment, '[')
+	if i < 0 {
+		return segment, nil
+	}
+	name, keys := segment[:i], strings.Split(strings.TrimSuffix(segment[i+1:], "]"), "][")
+	return name, keys
+}
+
+// element returns the element of the slice, array or map field with the name passed in at an index or key. Indexes that
+// are out of range and keys that aren't in the map are the zero value, the same as a nil pointer, except for the indexes of
+// arrays, whose length is known from their type
+func element(field reflect.Value, name, key string) reflect.Value {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.Kind() == reflect.Ptr && field.IsNil() {
+			field = reflect.Zero(field.Type().Elem())
+		} else {
+			field = field.Elem()
+		}
+	}
+	switch field.Kind() {
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 {
+			panic(fmt.Errorf("'%s' is not a valid index of '%s'", key, name))
+		} else if field.Kind() == reflect.Array && i >= field.Len() {
+			panic(fmt.Errorf("index %d is out of range of '%s' (%s)", i, name, field.Type()))
+		} else if i >= field.Len() {
+			return reflect.Zero(field.Type().Elem())
+		}
+		return field.Index(i)
+	case reflect.Map:
+		if field.Type().Key().Kind() != reflect.String {
+			panic(fmt.Errorf("'%s' must have string keys to be indexed", name))
+		}
+		elem := field.MapIndex(reflect.ValueOf(key).Convert(field.Type().Key()))
+		if !elem.IsValid() {
+			return reflect.Zero(field.Type().Elem())
+		} else if elem.Kind() == reflect.Interface && !elem.IsNil() {
+			return elem.Elem()
+		}
+		return elem
+	}
+	panic(fmt.Errorf("'%s' can not be indexed", name))
+}
+
+// equal compares two fields by kind, so that numbers of different sizes or signedness are equal when their values are
+func equal(a, b reflect.Value) bool {
+	if _, ok := magnitude(a); ok {
+		c, ok := compareIntegers(a, b)
+		return ok && c == 0
+	}
+	switch a.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return (b.Kind() == reflect.Float32 || b.Kind() == reflect.Float64) && a.Float() == b.Float()
+	case reflect.String:
+		return b.Kind() == reflect.String && a.String() == b.String()
+	}
+
+	// if the fields implement encoding.TextMarshaler, compare their text values
+	_, aIsMarshaler := a.Interface().(encoding.TextMarshaler)
+	_, bIsMarshaler := b.Interface().(encoding.TextMarshaler)
+	if aIsMarshaler && bIsMarshaler {
+		return stringify(a) == stringify(b)
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// stringify returns the text representation of a field
+func stringify(field reflect.Value) string {
+	if field.Kind() == reflect.String {
+		return field.String()
+	} else if marshaler, ok := field.Interface().(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(field.Interface())
+}
+
+// compareIntegers returns -1, 0 or 1 if the int or uint field a is less than, equal to or greater than the int or uint field b.
+// Unsigned values are compared as uint64, so that values above math.MaxInt64 don't wrap around
+func compareIntegers(a, b reflect.Value) (int, bool) {
+	am, ok := magnitude(a)
+	if !ok {
+		return 0, false
+	}
+	bm, ok := magnitude(b)
+	if !ok {
+		return 0, false
+	}
+	aIsNegative, bIsNegative := isNegative(a), isNegative(b)
+	switch {
+	case aIsNegative && !bIsNegative:
+		return -1, true
+	case !aIsNegative && bIsNegative:
+		return 1, true
+	case am == bm:
+		return 0, true
+	case (am < bm) != aIsNegative:
+		return -1, true
+	}
+	return 1, true
+}
+
+// magnitude returns the absolute value of an int or uint field as a uint64
+func magnitude(field reflect.Value) (uint64, bool) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := field.Int(); i < 0 {
+			return uint64(-i), true
+		}
+		return uint64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint(), true
+	}
+	return 0, false
+}
+
+// isNegative returns true if the field is an int less than 0
+func isNegative(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int() < 0
+	}
+	return false
+}
+
+// unquote removes the quotes from a string param
+func unquote(param string) string {
+	if l := len(param); l >= 2 && (param[0] == '\'' || param[0] == '"') && param[l-1] == param[0] {
+		return param[1 : l-1]
+	}
+	return param
+}
+
+// parseDefault parses a rule expression with the `DefaultRules`. It's set by init, since the `DefaultRules` contain the
+// rules that call it
+var parseDefault func(expression string) (*node, error)
+
+func init() {
+	parseDefault = func(expression string) (*node, error) {
+		return newParser().parse(expression, DefaultRules)
+	}
+}
+
+// parse parses a rule expression with the validator's rules, or with the `DefaultRules` if the rule is called directly
+func (ps *RuleParams) parse(expression string) (*node, error) {
+	if ps.validator == nil {
+		return parseDefault(expression)
+	}
+	return ps.validator.parse(expression)
+}
+
+// now returns the current time of the validator's clock
+func (ps *RuleParams) now() time.Time {
+	if ps.validator == nil || ps.validator.now == nil {
+		return time.Now()
+	}
+	return ps.validator.now()
+}
+
+// hasValue returns if the field is not nil or the golang devault/zero value. The field's own pointer has already been
+// dereferenced by the validator, so this only matters for the other fields that rules read, e.g. the fields named by `xor`.
+// Pointers to zero values are considered to have a value, unless the validator was configured with `Config.DerefPointers`
+func (ps *RuleParams) hasValue(field reflect.Value) bool {
+	if ps.validator != nil && ps.validator.derefPointers {
+		for field.Kind() == reflect.Ptr && !field.IsNil() {
+			field = field.Elem()
+		}
+	}
+	return hasValue(field)
+}
+
+// hasValue returns if the field is not nil or the golang devault/zero value
+func hasValue(field reflect.Value) bool {
+	if !field.IsValid() {
+		return false
+	}
+	fieldType := field.Type()
+	fieldKind := fieldType.Kind()
+	switch fieldKind {
+	case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
+		return !field.IsNil()
+	case reflect.Struct:
+		if fieldType == timeType {
+			return !field.Interface().(time.Time).IsZero()
+		}
+		return !reflect.DeepEqual(field.Interface(), reflect.Zero(fieldType).Interface())
 	default:
 		return field.IsValid() && !reflect.DeepEqual(field.Interface(), reflect.Zero(fieldType).Interface())
 	}
@@ -36,6 +36,8 @@ func (t *tokenType) MarshalText() ([]byte, error) {
 		return []byte("typeString"), nil
 	case typeSpace:
 		return []byte("typeSpace"), nil
+	case typeOptional:
+		return []byte("typeOptional"), nil
 	}
 	return nil, fmt.Errorf("not a valid type")
 }
@@ -55,10 +57,10 @@ const (
 	// typeEOF is the end of the file
 	typeEOF
 
-	// typeAnd is `&&`
+	// typeAnd is `&` or `&&`
 	typeAnd
 
-	// typeOr is `||`
+	// typeOr is `|` or `||`
 	typeOr
 
 	// typeFunction is a method signature
@@ -87,12 +89,19 @@ const (
 
 	// typeSpace is white space
 	typeSpace
+
+	// typeOptional is `?`
+	typeOptional
 )
 
 // type is a type emitted by the lexer
 type token struct {
 	typ tokenType
 	val string
+
+	// line and col are the 1 based line and column the token starts at
+	line int
+	col  int
 }
 
 func (t token) String() string {
@@ -123,6 +132,8 @@ func (t token) String() string {
 		return fmt.Sprintf("string: %s", t.val)
 	case typeSpace:
 		return fmt.Sprintf("space: %s", t.val)
+	case typeOptional:
+		return fmt.Sprintf("optional: %s", t.val)
 	}
 	if len(t.val) > 10 {
 		return fmt.Sprintf("%.10s...", t.val)
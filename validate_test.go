@@ -1,11 +1,19 @@
 package validator
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
 )
 
 const verboseLogs = false
@@ -30,11 +38,42 @@ func TestLexer(t *testing.T) {
 		}
 	}
 
+	// quotes and backslashes can be escaped inside of strings
+	l = newLexer(`func: 'it\'s', "say \"hi\"", 'C:\\', '\d'`)
+	for i, expected := range []string{`func`, `:`, ` `, `'it\'s'`, `,`, ` `, `"say \"hi\""`, `,`, ` `, `'C:\\'`, `,`, ` `, `'\d'`, ``} {
+		if token := l.Next(); token.typ == typeError {
+			t.Fatal(token.val)
+		} else if token.val != expected {
+			t.Fatalf("token[%d].val: '%+v' != '%+v'", i, token.val, expected)
+		}
+	}
+
+	// double operators are the same tokens as single operators
+	l = newLexer("one && two || three")
+	for i, expected := range []token{
+		{typeFunction, "one", 1, 1},
+		{typeSpace, " ", 1, 4},
+		{typeAnd, "&&", 1, 5},
+		{typeSpace, " ", 1, 7},
+		{typeFunction, "two", 1, 8},
+		{typeSpace, " ", 1, 11},
+		{typeOr, "||", 1, 12},
+		{typeSpace, " ", 1, 14},
+		{typeFunction, "three", 1, 15},
+		{typeEOF, "", 1, 20},
+	} {
+		if token := l.Next(); *token != expected {
+			t.Fatalf("token[%d]: '%+v' != '%+v'", i, *token, expected)
+		}
+	}
+
 	for _, s := range []string{
 		"func: param1, 2",
 		"f & t",
 		"t & (f | t | f)",
 		"t & (f | f | t) & t",
+		"f && t",
+		"t && (f || t | f)",
 	} {
 		t.Run(s, func(t *testing.T) {
 			l = newLexer(s)
@@ -48,6 +87,47 @@ func TestLexer(t *testing.T) {
 	}
 }
 
+func TestLexerPosition(t *testing.T) {
+	a := assert.New(t)
+
+	// tokens carry the line and column they start at
+	l := newLexer("required &\n  email |\n\tnumber: 'ß', 2")
+	for _, expected := range []token{
+		{typeFunction, "required", 1, 1},
+		{typeSpace, " ", 1, 9},
+		{typeAnd, "&", 1, 10},
+		{typeSpace, "\n  ", 1, 11},
+		{typeFunction, "email", 2, 3},
+		{typeSpace, " ", 2, 8},
+		{typeOr, "|", 2, 9},
+		{typeSpace, "\n\t", 2, 10},
+		{typeFunction, "number", 3, 2},
+		{typeColon, ":", 3, 8},
+		{typeSpace, " ", 3, 9},
+		{typeString, "'ß'", 3, 10},
+		{typeComma, ",", 3, 13},
+		{typeSpace, " ", 3, 14},
+		{typeNumber, "2", 3, 15},
+		{typeEOF, "", 3, 16},
+	} {
+		a.Equal(expected, *l.Next())
+	}
+
+	// lexing errors report the position of the offending token
+	l = newLexer("required &\n  email: 'unclosed")
+	for token := l.Next(); token.typ != typeEOF; token = l.Next() {
+		if token.typ == typeError {
+			a.Equal(2, token.line)
+			a.Equal(10, token.col)
+			break
+		}
+	}
+
+	// as do parsing errors
+	_, err := newParser().parse("required &\n  : email", Rules{"required": Required, "email": Email})
+	a.EqualError(err, "bad ':' at 13 (line 2, column 3)")
+}
+
 func TestParser(t *testing.T) {
 	parser := newParser()
 	parser.debug = verboseLogs
@@ -58,6 +138,7 @@ func TestParser(t *testing.T) {
 		return fmt.Errorf("error called")
 	}
 	var params []string
+	var typedParams []Param
 	rules := map[string]Rule{
 		"t": tr,
 		"f": fl,
@@ -67,11 +148,66 @@ func TestParser(t *testing.T) {
 		"d": fl,
 		"e": tr,
 		"func": func(ps *RuleParams) error {
+			params = ps.Params
+			typedParams = ps.TypedParams
+			return nil
+		},
+		"my-rule": func(ps *RuleParams) error {
 			params = ps.Params
 			return nil
 		},
 	}
 
+	// quoted rule names can contain any character
+	for _, s := range []string{
+		"'my-rule'",
+		`"my-rule": 1, 'two' & t`,
+		"f | ('my-rule' & t)",
+	} {
+		if isValid := t.Run(s, func(t *testing.T) {
+			params = nil
+			if parsed, err := parser.parse(s, rules); err != nil {
+				t.Fatalf("parse failed: %s", err)
+			} else if err := parsed.execute(&RuleParams{}); err != nil {
+				t.Fatalf("execution failed: %s", err)
+			} else if s == `"my-rule": 1, 'two' & t` {
+				assert.Equal(t, []string{"1", "'two'"}, params)
+			}
+		}); !isValid {
+			t.Fatal("failed")
+			return
+		}
+	}
+	if _, err := parser.parse("my-rule", rules); err == nil {
+		t.Fatal("unquoted rule names can't contain dashes")
+	} else if _, err := parser.parse("'unknown-rule'", rules); err == nil || err.Error() != "'unknown-rule' is not a valid rule" {
+		t.Fatalf("unknown quoted rule names should fail, got %v", err)
+	}
+
+	// typed params are unquoted and parsed
+	if parsed, err := parser.parse(`func: "it's", one, 0x1F, 2.5, false`, rules); err != nil {
+		t.Fatal(err)
+	} else if err := parsed.execute(&RuleParams{}); err != nil {
+		t.Fatalf("execution failed: %s", err)
+	} else {
+		assert.Equal(t, []Param{
+			{Kind: ParamString, Value: "it's"},
+			{Kind: ParamIdentifier, Value: "one"},
+			{Kind: ParamNumber, Value: "0x1F", Number: 31},
+			{Kind: ParamNumber, Value: "2.5", Number: 2.5},
+			{Kind: ParamBool, Value: "false"},
+		}, typedParams)
+	}
+
+	// parsed params are unescaped
+	if parsed, err := parser.parse(`func: 'it\'s', "say \"hi\"", 'C:\\', '\d'`, rules); err != nil {
+		t.Fatal(err)
+	} else if err := parsed.execute(&RuleParams{}); err != nil {
+		t.Fatalf("execution failed: %s", err)
+	} else {
+		assert.Equal(t, []string{`'it's'`, `"say "hi""`, `'C:\'`, `'\d'`}, params)
+	}
+
 	// test function
 	for _, s := range []string{
 		"func: 'hello world', 2",
@@ -84,6 +220,10 @@ func TestParser(t *testing.T) {
 			} else {
 				a := assert.New(t)
 				a.Equal(params, []string{`'hello world'`, `2`})
+				a.Equal(typedParams, []Param{
+					{Kind: ParamString, Value: "hello world"},
+					{Kind: ParamNumber, Value: "2", Number: 2},
+				})
 			}
 		}); !isValid {
 			t.Fatal("failed")
@@ -96,6 +236,9 @@ func TestParser(t *testing.T) {
 		"t & t",
 		"t & (f | t | f)",
 		"a & (b | c | d) & e",
+		"t && t",
+		"t && (f || t || f)",
+		"a && (b || c | d) & e",
 	} {
 		if isValid := t.Run(s, func(t *testing.T) {
 			if parsed, err := parser.parse(s, rules); err != nil {
@@ -115,6 +258,9 @@ func TestParser(t *testing.T) {
 		"t & (f | t & f)",
 		"t & (f | f & t) & t",
 		"t & (f | f | t) & f",
+		"t && f",
+		"t && (f || t && f)",
+		"t & (f || f && t) && t",
 	} {
 		if isValid := t.Run(s, func(t *testing.T) {
 			if parsed, err := parser.parse(s, rules); err != nil {
@@ -128,6 +274,22 @@ func TestParser(t *testing.T) {
 		}
 	}
 
+	// optional rules are skipped when the field is empty
+	for _, s := range []string{"f?", "f:1,'two'?", "t & f?", "(f? | f) & t"} {
+		if isValid := t.Run(s, func(t *testing.T) {
+			if parsed, err := parser.parse(s, rules); err != nil {
+				t.Fatalf("parse failed: %s", err)
+			} else if err := parsed.execute(&RuleParams{Field: reflect.ValueOf("")}); err != nil {
+				t.Fatalf("execution failed: %s", err)
+			} else if err := parsed.execute(&RuleParams{Field: reflect.ValueOf("set")}); err == nil {
+				t.Fatal("there should be an error returned")
+			}
+		}); !isValid {
+			t.Fatal("failed")
+			return
+		}
+	}
+
 	// parse with bad bad syntax
 	for _, s := range []string{
 		"t f",
@@ -136,6 +298,17 @@ func TestParser(t *testing.T) {
 		"t & (f | f t) & f",
 		"t & (f | f | t & f",
 		"t & : f",
+		"t && && f",
+		"t &&& f",
+		"t ||| f",
+		"t?:1",
+		"t??",
+		"t:?",
+		"? t",
+		"t & ?",
+		"t &",
+		"t |",
+		"(t & f &)",
 	} {
 		if isValid := t.Run(s, func(t *testing.T) {
 			if _, err := parser.parse(s, rules); err == nil {
@@ -146,6 +319,100 @@ func TestParser(t *testing.T) {
 			return
 		}
 	}
+
+	// parse errors report the position of the bad token
+	a := assert.New(t)
+	for s, expected := range map[string]ParseError{
+		"t:?":                ParseError{Offset: 2, Line: 1, Column: 3, Token: "?", Expected: "param"},
+		"t & ?":              ParseError{Offset: 4, Line: 1, Column: 5, Token: "?", Expected: "rule"},
+		"t (f | t & f)":      ParseError{Offset: 2, Line: 1, Column: 3, Token: "(", Expected: "operator"},
+		"t & (f | f & t) t":  ParseError{Offset: 16, Line: 1, Column: 17, Token: "t", Expected: "operator"},
+		"t & (f | f | t & f": ParseError{Offset: 18, Line: 1, Column: 19, Token: "", Expected: "token"},
+		"t & : f":            ParseError{Offset: 4, Line: 1, Column: 5, Token: ":", Expected: "rule"},
+		"t &&& f":            ParseError{Offset: 4, Line: 1, Column: 5, Token: "&", Expected: "rule"},
+		"t &\n  x":           ParseError{Offset: 6, Line: 2, Column: 3, Token: "x", Expected: "rule"},
+		"t & 'unclosed":      ParseError{Offset: 4, Line: 1, Column: 5, Token: "'", Expected: "token"},
+	} {
+		_, err := parser.parse(s, rules)
+		var pe *ParseError
+		if a.True(errors.As(err, &pe), s) {
+			pe.err = nil
+			a.Equal(expected, *pe, s)
+		}
+	}
+
+	// dangling operators are reported by the operator that is dangling
+	for s, expected := range map[string]string{
+		"t &":       "bad '&' at 3 (line 1, column 4)",
+		"t |":       "bad '|' at 3 (line 1, column 4)",
+		"(t & f &)": "bad '&' at 8 (line 1, column 9)",
+	} {
+		_, err := parser.parse(s, rules)
+		a.EqualError(err, expected, s)
+	}
+
+	// parse errors are returned by CheckSyntax
+	var s struct {
+		Field string `validate:"required & : email"`
+	}
+	var pe *ParseError
+	a.True(errors.As(New().CheckSyntax(&s), &pe))
+	a.Equal(":", pe.Token)
+	a.Equal(11, pe.Offset)
+}
+
+func TestParse(t *testing.T) {
+	a := assert.New(t)
+	rules := Rules{"a": Required, "b": Required, "c": Required}
+
+	// a & (b | c)
+	root, err := Parse("a & (b | c: 'one', 2)", rules)
+	a.Nil(err)
+	a.Equal(NodeAnd, root.Type())
+	a.Equal("", root.Rule())
+	children := root.Children()
+	a.Len(children, 2)
+	a.Equal(NodeRule, children[0].Type())
+	a.Equal("a", children[0].Rule())
+	a.Nil(children[0].Params())
+	a.Nil(children[0].Children())
+	a.Equal(NodeOr, children[1].Type())
+	or := children[1].Children()
+	a.Len(or, 2)
+	a.Equal("b", or[0].Rule())
+	a.Equal("c", or[1].Rule())
+	a.Equal([]Param{{Kind: ParamString, Value: "one"}, {Kind: ParamNumber, Value: "2", Number: 2}}, or[1].Params())
+
+	// references to other fields
+	root, err = Parse("c: $Max, root.Min, '$Quoted'", rules)
+	a.Nil(err)
+	a.Equal([]Param{{Kind: ParamReference, Value: "Max"}, {Kind: ParamIdentifier, Value: "root.Min"}, {Kind: ParamString, Value: "$Quoted"}}, root.Params())
+	_, err = Parse("c: $", rules)
+	a.Error(err)
+	root, err = Parse("c: $Items[0].Price, $Prices[usd], $Grid[1][2]", rules)
+	a.Nil(err)
+	a.Equal([]Param{{Kind: ParamReference, Value: "Items[0].Price"}, {Kind: ParamReference, Value: "Prices[usd]"}, {Kind: ParamReference, Value: "Grid[1][2]"}}, root.Params())
+	for _, s := range []string{"c: $Items[", "c: $Items[]", "c: $Items[0", "c: Items[0]"} {
+		_, err = Parse(s, rules)
+		a.Error(err, s)
+	}
+
+	// optional rules
+	root, err = Parse("a? & b", rules)
+	a.Nil(err)
+	a.True(root.Children()[0].IsOptional())
+	a.False(root.Children()[1].IsOptional())
+
+	// the tree can't be changed through the params
+	or[1].Params()[0].Value = "changed"
+	a.Equal("one", or[1].Params()[0].Value)
+
+	// the default rules are used when none are passed in
+	root, err = Parse("required & email", nil)
+	a.Nil(err)
+	a.Equal(NodeAnd, root.Type())
+	_, err = Parse("a & unknown", rules)
+	a.EqualError(err, "'unknown' is not a valid rule")
 }
 
 func TestValidator(t *testing.T) {
@@ -180,6 +447,16 @@ func TestValidator(t *testing.T) {
 		a.Nil(v.Validate(&s1))
 		a.Nil(v1.Validate(&s))
 		a.NotNil(v1.Validate(&s1))
+	}) && t.Run("test name tag parsing", func(t *testing.T) {
+		type s struct {
+			FirstName string `json:"firstName" form:"first_name" validate:"required"`
+			LastName  string `json:"lastName" form:"last_name,omitempty" validate:"and:Email"`
+			Email     string `json:"email" form:"email_address"`
+			Phone     string `json:"phone" validate:"required"`
+		}
+		a := assert.New(t)
+		a.EqualError(New().Validate(&s{}), `["'firstName' is required","'lastName' and 'email' must be set","'phone' is required"]`)
+		a.EqualError(New(&Config{NameTag: "form"}).Validate(&s{}), `["'first_name' is required","'last_name' and 'email_address' must be set","'Phone' is required"]`)
 	}) && t.Run("test and / or logic", func(t *testing.T) {
 		// create a rule that always passes and a rule that always fails fails
 		rules := Rules{
@@ -238,283 +515,2967 @@ func TestValidator(t *testing.T) {
 		}
 		a := assert.New(t)
 		v := New()
-		if passed := a.EqualError(v.CheckSyntax(&s{}), `["bad ':' at 11"]`); !passed {
+		if passed := a.EqualError(v.CheckSyntax(&s{}), `["bad ':' at 11 (line 1, column 12)"]`); !passed {
 			t.FailNow()
 		}
-	}); !pass {
-		t.Fatal("tests failed!")
-	}
-}
-
-func TestRules(t *testing.T) {
-	debug = verboseLogs
-	if pass := t.Run("required", func(t *testing.T) {
-		var s1 struct {
-			Field string `validate:"required"`
+	}) && t.Run("traverses interface fields", func(t *testing.T) {
+		type nested struct {
+			Field string `json:"field" validate:"required"`
 		}
-		s2 := struct {
-			Field string `validate:"required"`
-		}{
-			Field: "populated",
+		type s struct {
+			Nested interface{} `json:"nested"`
 		}
 		v := New()
 		a := assert.New(t)
-		a.EqualError(v.Validate(&s1), `["'Field' is required"]`)
-		a.Nil(v.Validate(&s2))
-	}) && t.Run("empty", func(t *testing.T) {
-		var s1 struct {
-			Field string `validate:"empty | fail"`
+		a.EqualError(v.Validate(&s{Nested: nested{}}), `["'field' is required"]`)
+		a.EqualError(v.Validate(&s{Nested: &nested{}}), `["'field' is required"]`)
+		a.EqualError(v.Validate(&s{Nested: []nested{{Field: "set"}, {}}}), `["'field' is required"]`)
+		a.EqualError(v.Validate(&s{Nested: map[string]nested{"a": {}}}), `["'field' is required"]`)
+		a.Nil(v.Validate(&s{Nested: nested{Field: "set"}}))
+		a.Nil(v.Validate(&s{Nested: (*nested)(nil)}))
+		a.Nil(v.Validate(&s{}))
+	}) && t.Run("skips fields with - and nodive", func(t *testing.T) {
+		type nested struct {
+			Name  string `json:"name"`
+			Field string `json:"field" validate:"required"`
 		}
-		s2 := struct {
-			Field string `validate:"empty | fail"`
-		}{
-			Field: "populated",
+		type s struct {
+			Ignored    nested  `json:"ignored" validate:"-"`
+			NotDived   *nested `json:"notDived" validate:"required & nodive"`
+			Traversed  nested  `json:"traversed"`
+			Unaffected string  `json:"unaffected" validate:"required"`
 		}
-		v := New(&Config{
-			Rules: Rules{
-				"empty": Empty,
-				"fail": func(*RuleParams) error {
-					return errors.New("this will always fail")
-				},
-			},
-		})
+		v := New()
 		a := assert.New(t)
-		a.Nil(v.Validate(&s1))
-		a.EqualError(v.Validate(&s2), `["this will always fail"]`)
-	}) && t.Run("email", func(t *testing.T) {
-		var s1 struct {
-			EmailAddress string `validate:"email"`
+		a.EqualError(v.Validate(&s{}), `["'notDived' is required","'field' is required","'unaffected' is required"]`)
+		a.Nil(v.Validate(&s{NotDived: &nested{Name: "set"}, Traversed: nested{Field: "set"}, Unaffected: "set"}))
+	}) && t.Run("caches struct types", func(t *testing.T) {
+		a := assert.New(t)
+		for _, s := range []benchmarkStruct{{}, {One: "one", Two: "two", Three: "three"}, {Four: "a@b.c", Five: 5, Seven: 7}} {
+			// the first validation caches the type, and the second reads it from the cache
+			v := New().(*validator)
+			uncached := v.Validate(&s)
+			a.Contains(v.types, reflect.TypeOf(s))
+			cached := v.Validate(&s)
+			a.Equal(uncached, cached)
+
+			// a validator whose cache is cleared before every validation parses the tags every time
+			bypassed := New().(*validator)
+			for i := 0; i < 2; i++ {
+				bypassed.types = make(map[reflect.Type][]field)
+				a.Equal(cached, bypassed.Validate(&s))
+			}
 		}
-		var s2 struct {
-			EmailAddress uint `validate:"email"`
+	}) && t.Run("dereferences pointers", func(t *testing.T) {
+		type s struct {
+			Count *int `json:"count" validate:"required"`
+			Limit *int `json:"limit" validate:"xor:Total"`
+			Total *int `json:"total"`
 		}
-		v := New(&Config{
-			Rules: Rules{
-				"email": Email,
-			},
-		})
+		zero, five := 0, 5
+		v, deref := New(), New(&Config{DerefPointers: true})
 		a := assert.New(t)
 
-		// empty emails fail
-		a.EqualError(v.Validate(&s1), `["'EmailAddress' must be a valid email address"]`)
+		// the field's own pointer is always dereferenced before its rules are applied
+		for _, v := range []Validator{v, deref} {
+			a.EqualError(v.Validate(&s{Limit: &five}), `["'count' is required"]`)
+			a.EqualError(v.Validate(&s{Count: &zero, Limit: &five}), `["'count' is required"]`)
+			a.Nil(v.Validate(&s{Count: &five, Limit: &five}))
+			a.Nil(v.Validate(&s{Count: &five, Total: &five}))
+		}
 
-		// incorrect emails fail
-		s1.EmailAddress = "notAnEmail@"
-		a.EqualError(v.Validate(&s1), `["'EmailAddress' must be a valid email address"]`)
+		// by default, a pointer to a zero value is set
+		a.EqualError(v.Validate(&s{Count: &five, Limit: &five, Total: &zero}), `["either 'limit' or 'total' must be set"]`)
+		a.Nil(v.Validate(&s{Count: &five, Total: &zero}))
 
-		// empty emails fail
-		s1.EmailAddress = "hello@dealyze.com"
-		a.Nil(v.Validate(&s1))
+		// when pointers are dereferenced, a pointer to a zero value is not set
+		a.Nil(deref.Validate(&s{Count: &five, Limit: &five, Total: &zero}))
+		a.EqualError(deref.Validate(&s{Count: &five, Total: &zero}), `["either 'limit' or 'total' must be set"]`)
+	}) && t.Run("serializes errors as a json object", func(t *testing.T) {
+		type user struct {
+			Email string `json:"email" validate:"email"`
+			Age   int    `json:"age" validate:"number:18"`
+		}
+		type s struct {
+			User   user     `json:"user"`
+			Emails []string `json:"emails" validate:"required & each:'email'"`
+		}
+		a := assert.New(t)
+		var errs FieldErrors
+		if err := New().Validate(&s{Emails: []string{"a", "b"}}); a.True(errors.As(err, &errs)) {
+			bs, err := errs.JSONObject()
+			a.Nil(err)
+			a.JSONEq(`{
+				"user.email": "'email' must be a valid email address",
+				"user.age": "'age' must be 18 or more",
+				"emails[0]": "'emails[0]' must be a valid email address",
+				"emails[1]": "'emails[1]' must be a valid email address"
+			}`, string(bs))
+		}
 
-		// syntax check
-		a.EqualError(v.CheckSyntax(&s2), "the email tag must be applied to a string")
-	}) && t.Run("password", func(t *testing.T) {
-		var s1 struct {
-			Password string `validate:"password"`
+		// errors that share a path are grouped into an array
+		errs = FieldErrors{
+			&FieldError{Path: "user.password", Message: errors.New("too short")},
+			&FieldError{Path: "user.password", Message: errors.New("needs a number")},
+			&FieldError{Path: "user.name", Message: errors.New("required")},
 		}
-		var s2 struct {
-			Password []byte `validate:"password"`
+		bs, err := errs.JSONObject()
+		a.Nil(err)
+		a.JSONEq(`{"user.password":["too short","needs a number"],"user.name":"required"}`, string(bs))
+	}) && t.Run("skips empty fields", func(t *testing.T) {
+		type s struct {
+			Email    string `json:"email" validate:"email"`
+			Required string `json:"required" validate:"required & email"`
+			Phone    string `json:"phone" validate:"or:Mobile"`
+			Mobile   string `json:"mobile"`
 		}
-		v := New(&Config{
-			Rules: Rules{
-				"password": Password,
-			},
-		})
+		v, skip := New(), New(&Config{SkipEmpty: true})
 		a := assert.New(t)
+		a.EqualError(v.Validate(&s{}), `["'email' must be a valid email address","'required' is required","either 'phone' and/or 'mobile' must be set"]`)
+		a.EqualError(skip.Validate(&s{}), `["'required' is required","either 'phone' and/or 'mobile' must be set"]`)
+		a.Nil(skip.Validate(&s{Required: "a@b.com", Mobile: "5551234567"}))
+		a.EqualError(skip.Validate(&s{Email: "notAnEmail", Required: "a@b.com", Mobile: "5551234567"}), `["'email' must be a valid email address"]`)
 
-		// empty emails fail
-		a.EqualError(v.Validate(&s1), `["'Password' must be a at least 6 characters long and contain at least one number or special character (eg. @!#)"]`)
+		// syntax checks still run the rules of empty fields
+		var s1 struct {
+			Email int `json:"email" validate:"email"`
+		}
+		a.EqualError(skip.CheckSyntax(&s1), `["the email tag must be applied to a string"]`)
+	}) && t.Run("valid", func(t *testing.T) {
+		type s struct {
+			Email string `json:"email" validate:"required & email"`
+			Phone string `json:"phone" validate:"empty | number"`
+		}
+		v := New()
+		a := assert.New(t)
+		for _, i := range []interface{}{
+			&s{Email: "a@b.com"},
+			&s{Email: "a@b.com", Phone: "5551234567"},
+			&s{},
+			&s{Email: "notAnEmail"},
+			[]s{{Email: "a@b.com"}, {Phone: "notANumber"}},
+		} {
+			a.Equal(v.Validate(i) == nil, v.Valid(i))
+			a.Equal(Validate(i) == nil, Valid(i))
+		}
+		a.True(v.Valid(&s{Email: "a@b.com"}))
+		a.False(v.Valid(&s{}, language.Spanish))
+	}) && t.Run("must validate", func(t *testing.T) {
+		type s struct {
+			Email string `json:"email" validate:"required & email"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.NotPanics(func() { v.MustValidate(&s{Email: "a@b.com"}) })
+		a.NotPanics(func() { MustValidate(&s{Email: "a@b.com"}) })
+		a.PanicsWithError(`["'email' is required"]`, func() { v.MustValidate(&s{}) })
+		a.PanicsWithError(`["'email' es obligatorio"]`, func() { MustValidate(&s{}, language.Spanish) })
+	}) && t.Run("adds type names", func(t *testing.T) {
+		type s struct {
+			Count int    `json:"count" validate:"number:1,100"`
+			Size  *uint8 `json:"size" validate:"required"`
+			Phone string `json:"phone" validate:"or:Email"`
+			Email string `json:"email"`
+		}
+		v, typed := New(), New(&Config{TypeNames: true})
+		a := assert.New(t)
+		a.EqualError(v.Validate(&s{}), `["'count' must be 1 to 100","'size' is required","either 'phone' and/or 'email' must be set"]`)
+		a.EqualError(typed.Validate(&s{}), `["'count' (int) must be 1 to 100","'size' (*uint8) is required","either 'phone' (string) and/or 'email' must be set"]`)
+		a.EqualError(typed.Validate(&s{Count: 200, Phone: "5551234567"}, language.Spanish), `["'count' (int) debe ser de 1 a 100","'size' (*uint8) es obligatorio"]`)
+		size := uint8(1)
+		a.Nil(typed.Validate(&s{Count: 1, Size: &size, Phone: "5551234567"}))
 
-		// password without special characters fails
-		s1.Password = "notavalidpassword"
-		a.EqualError(v.Validate(&s1), `["'Password' must be a at least 6 characters long and contain at least one number or special character (eg. @!#)"]`)
+		// pointers are named by their declared type, whether they're set or not
+		type s1 struct {
+			Size *uint8 `json:"size" validate:"number:1,10"`
+		}
+		size = 20
+		a.EqualError(typed.Validate(&s1{}), `["'size' (*uint8) must be 1 to 10"]`)
+		a.EqualError(typed.Validate(&s1{&size}), `["'size' (*uint8) must be 1 to 10"]`)
+	}) && t.Run("checks the syntax of every field", func(t *testing.T) {
+		type nested struct {
+			Size string `json:"size" validate:"divisible_by:Count"`
+		}
+		var s struct {
+			Email  int      `json:"email" validate:"email"`
+			Name   string   `json:"name" validate:"required"`
+			Color  string   `json:"color" validate:"contrast:white"`
+			Nested []nested `json:"nested"`
+		}
+		s.Nested = []nested{{}}
+		v := New()
+		a := assert.New(t)
+		err := v.CheckSyntax(&s)
+		a.EqualError(err, `["the email tag must be applied to a string","contrast requires a background color and a ratio","the divisible_by tag must be applied to an integer"]`)
+		var errs FieldErrors
+		if a.True(errors.As(err, &errs)) && a.Len(errs, 3) {
+			a.Equal("email", errs[0].(*FieldError).Path)
+			a.Equal("color", errs[1].(*FieldError).Path)
+			a.Equal("nested[0].size", errs[2].(*FieldError).Path)
+		}
+	}) && t.Run("lists rule names", func(t *testing.T) {
+		var expected []string
+		for name := range DefaultRules {
+			expected = append(expected, name)
+		}
+		a := assert.New(t)
+		a.ElementsMatch(expected, New().RuleNames())
+		a.True(sort.StringsAreSorted(RuleNames()))
 
-		// password that is too short fails
-		s1.Password = "abc12"
-		a.EqualError(v.Validate(&s1), `["'Password' must be a at least 6 characters long and contain at least one number or special character (eg. @!#)"]`)
+		// rules added after the validator was created are listed too
+		rules := Rules{"required": Required}
+		v := New(&Config{Rules: rules})
+		a.Equal([]string{"required"}, v.RuleNames())
+		rules.Add("custom", func(*RuleParams) error {
+			return nil
+		})
+		a.Equal([]string{"custom", "required"}, v.RuleNames())
+	}) && t.Run("checks the syntax of rules declared by a spec", func(t *testing.T) {
+		prefix := RuleSpec{
+			MinParams: 1,
+			MaxParams: 1,
+			Kinds:     []reflect.Kind{reflect.String},
+			Fn: func(ps *RuleParams) error {
+				if strings.HasPrefix(ps.Field.String(), ps.TypedParams[0].Value) {
+					return nil
+				}
+				return fmt.Errorf("'%s' must start with %s", ps.FieldName, ps.TypedParams[0].Value)
+			},
+		}
+		rules := Rules{}
+		rules.AddSpec("prefix", prefix)
+		rules.AddSpec("any", RuleSpec{Kinds: []reflect.Kind{reflect.Int, reflect.Slice, reflect.Map}, Fn: Required})
+		v := New(&Config{Rules: rules, ExtendDefaults: true})
+		a := assert.New(t)
 
-		// valid password succeeds
-		s1.Password = "abc123"
-		a.Nil(v.Validate(&s1))
+		// correctly used rules are called
+		type s struct {
+			ID string `json:"id" validate:"prefix:usr_"`
+		}
+		a.Nil(v.CheckSyntax(&s{}))
+		a.Nil(v.Validate(&s{"usr_1"}))
+		a.EqualError(v.Validate(&s{"1"}), `["'id' must start with usr_"]`)
 
-		// syntax check
-		a.EqualError(v.CheckSyntax(&s2), "the password tag must be applied to a string")
-	}) && t.Run("number", func(t *testing.T) {
+		// misused rules are reported without the rule checking for itself
 		var s1 struct {
-			Number string `validate:"number"`
+			ID string `json:"id" validate:"prefix"`
 		}
 		var s2 struct {
-			Number string `validate:"number:2,4"`
+			ID string `json:"id" validate:"prefix:usr_,acc_"`
 		}
 		var s3 struct {
-			Number int `validate:"number:2,4"`
+			ID int `json:"id" validate:"prefix:usr_"`
 		}
 		var s4 struct {
-			Int     int     `validate:"number"`
-			Int8    int8    `validate:"number"`
-			Int16   int16   `validate:"number"`
-			Int32   int32   `validate:"number"`
-			Int64   int64   `validate:"number"`
-			Uint    uint    `validate:"number"`
-			Uint8   uint8   `validate:"number"`
-			Uint16  uint16  `validate:"number"`
-			Uint32  uint32  `validate:"number"`
-			Uint64  uint64  `validate:"number"`
-			Float32 float32 `validate:"number"`
-			Float64 float64 `validate:"number"`
+			ID string `json:"id" validate:"any"`
 		}
-		v := New(&Config{
-			Rules: Rules{
-				"number": Number,
-			},
-		})
+		a.EqualError(v.CheckSyntax(&s1), `["prefix requires at least 1 parameter(s)"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["prefix accepts at most 1 parameter(s)"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the prefix tag must be applied to a string"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["the any tag must be applied to an int, slice or map"]`)
+
+		// specs can be registered with the default rules
+		RegisterRuleSpec("test_prefix", prefix)
+		defer delete(DefaultRules, "test_prefix")
+		var s5 struct {
+			ID string `json:"id" validate:"test_prefix"`
+		}
+		a.EqualError(CheckSyntax(&s5), `["test_prefix requires at least 1 parameter(s)"]`)
+	}) && t.Run("validates the values of source methods", func(t *testing.T) {
 		a := assert.New(t)
+		v := New()
 
-		// empty strings fail
-		a.NotNil(v.Validate(&s1))
+		// the computed total is validated instead of the field
+		o := sourceOrder{Items: []float64{10, 5}, Discount: 20}
+		a.EqualError(v.Validate(&o), `["'netTotal' must be 0 or more"]`)
+		o.Discount = 10
+		a.Nil(v.Validate(&o))
+		a.EqualError(v.Validate([]sourceOrder{{Items: []float64{1}, Discount: 2}}), `["'netTotal' must be 0 or more"]`)
 
-		// incorrect string number fails
-		s1.Number = "not a number"
-		a.NotNil(v.Validate(&s1))
+		// methods with the wrong signature are syntax errors
+		var s1 struct {
+			Total int `json:"total" validate:"number:0" source:"Missing"`
+		}
+		a.EqualError(v.CheckSyntax(&s1), `["'.Missing' is not a valid method"]`)
+		a.EqualError(v.CheckSyntax(&sourceSum{}), `["'sourceSum.Sum' must take no arguments and return a single value"]`)
+	}) && t.Run("reports all errors", func(t *testing.T) {
+		type s struct {
+			Email string `json:"email" validate:"required & email"`
+			Phone string `json:"phone" validate:"(letters & number) | email"`
+		}
+		v, all := New(), New(&Config{ReportAll: true})
+		a := assert.New(t)
 
-		// strings that is only numbers works
-		s1.Number = "12345"
-		a.Nil(v.Validate(&s1))
+		// every failure of a field is reported
+		a.EqualError(v.Validate(&s{Phone: "a@b.com"}), `["'email' is required"]`)
+		a.EqualError(all.Validate(&s{Phone: "a@b.com"}), `["'email' is required","'email' must be a valid email address"]`)
+		a.EqualError(all.Validate(&s{Email: "notAnEmail", Phone: "a@b.com"}), `["'email' must be a valid email address"]`)
 
-		// digit range
-		s2.Number = "0"
-		a.EqualError(v.Validate(&s2), `["'Number' must be 2 to 4 digits"]`)
-		s2.Number = "000"
-		a.Nil(v.Validate(&s2))
-		s2.Number = "00000"
-		a.EqualError(v.Validate(&s2), `["'Number' must be 2 to 4 digits"]`)
+		// ors pass if either side passes, and report both sides when neither does
+		a.Nil(all.Validate(&s{Email: "a@b.com", Phone: "a@b.com"}))
+		a.EqualError(v.Validate(&s{Email: "a@b.com", Phone: "123"}), `["'phone' must be a valid email address"]`)
+		a.EqualError(all.Validate(&s{Email: "a@b.com", Phone: "123"}), `["'phone' can only contain letters and spaces","'phone' must be a valid email address"]`)
+		a.EqualError(all.Validate(&s{Email: "a@b.com", Phone: "a-1"}), `["'phone' can only contain letters and spaces","'phone' must contain only numbers","'phone' must be a valid email address"]`)
+	}) && t.Run("caches results", func(t *testing.T) {
+		var calls int
+		rules := Rules{
+			"counted": func(ps *RuleParams) error {
+				calls++
+				return Email(ps)
+			},
+		}
+		type s struct {
+			Email string `json:"email" validate:"counted"`
+		}
+		v := New(&Config{Rules: rules, CacheResults: true})
+		a := assert.New(t)
 
-		// int range
-		s3.Number = 1
-		a.EqualError(v.Validate(&s3), `["'Number' must be 2 to 4"]`)
-		s3.Number = 3
-		a.Nil(v.Validate(&s3))
-		s3.Number = 5
-		a.EqualError(v.Validate(&s3), `["'Number' must be 2 to 4"]`)
+		// unchanged values are only validated once per language
+		value := s{"notAnEmail"}
+		a.EqualError(v.Validate(&value), `["'email' must be a valid email address"]`)
+		a.EqualError(v.Validate(&value), `["'email' must be a valid email address"]`)
+		a.EqualError(v.Validate(value), `["'email' must be a valid email address"]`)
+		a.Equal(1, calls)
+		a.EqualError(v.Validate(&value, language.Spanish), `["'email' debe ser una dirección de correo electrónico válida"]`)
+		a.Equal(2, calls)
 
-		// all numbers are valid
-		a.Nil(v.Validate(&s4))
+		// mutated values are validated again
+		value.Email = "a@b.com"
+		a.Nil(v.Validate(&value))
+		a.Nil(v.Validate(&value))
+		a.Equal(3, calls)
 
-	}) && t.Run("eq", func(t *testing.T) {
-		type s struct {
-			Uint   uint   `json:"a" validate:"eq:1,2,3"`
-			Int    int    `json:"b" validate:"eq:1,2,3"`
-			String string `json:"c" validate:"eq:1,2,3"`
-		}
-		s1 := s{
-			1, 2, "3",
+		// clearing the cache validates values again
+		v.ClearCache()
+		a.Nil(v.Validate(&value))
+		a.Equal(4, calls)
+
+		// values that contain references are never cached
+		type s1 struct {
+			Emails []s `json:"emails"`
+		}
+		list := s1{[]s{{"a@b.com"}}}
+		a.Nil(v.Validate(&list))
+		list.Emails[0].Email = "notAnEmail"
+		a.EqualError(v.Validate(&list), `["'email' must be a valid email address"]`)
+		a.Equal(6, calls)
+
+		// validators without the cache always validate
+		calls = 0
+		v = New(&Config{Rules: rules})
+		a.Nil(v.Validate(&value))
+		a.Nil(v.Validate(&value))
+		a.Equal(2, calls)
+	}) && t.Run("extends the default rules", func(t *testing.T) {
+		rules := Rules{
+			"company": func(ps *RuleParams) error {
+				if strings.HasSuffix(ps.Field.String(), "@example.com") {
+					return nil
+				}
+				return fmt.Errorf("'%s' must be a company email address", ps.FieldName)
+			},
+			"letters": func(ps *RuleParams) error {
+				return fmt.Errorf("'%s' is overridden", ps.FieldName)
+			},
+		}
+		type s struct {
+			Email string `json:"email" validate:"email & company"`
+			Name  string `json:"name" validate:"letters"`
+		}
+		v := New(&Config{Rules: rules, ExtendDefaults: true})
+		a := assert.New(t)
+		a.EqualError(v.Validate(&s{Email: "notAnEmail"}), `["'email' must be a valid email address","'name' is overridden"]`)
+		a.EqualError(v.Validate(&s{Email: "a@b.com"}), `["'email' must be a company email address","'name' is overridden"]`)
+		a.EqualError(v.Validate(&s{Email: "a@example.com"}), `["'name' is overridden"]`)
+		a.Equal(len(DefaultRules)+1, len(v.RuleNames()))
+
+		// the default rules are left untouched
+		a.Nil(DefaultRules["company"])
+		var s1 struct {
+			Name string `json:"name" validate:"letters"`
+		}
+		s1.Name = "name"
+		a.Nil(New().Validate(&s1))
+
+		// without extending the defaults, only the rules passed in are used
+		a.EqualError(New(&Config{Rules: rules}).CheckSyntax(&s{}), `["'email' is not a valid rule"]`)
+	}) && t.Run("times out syntax checks", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		rules := Rules{
+			"block": func(*RuleParams) error {
+				<-block
+				return nil
+			},
+			"panic": func(*RuleParams) error {
+				panic("the panic tag must be applied to a string")
+			},
+		}
+		var s struct {
+			Field string `json:"field" validate:"block"`
+		}
+		var s1 struct {
+			Field int `json:"field" validate:"panic"`
+		}
+		v := New(&Config{Rules: rules, SyntaxCheckTimeout: 10 * time.Millisecond})
+		a := assert.New(t)
+		a.EqualError(v.CheckSyntax(&s), "the syntax check timed out after 10ms")
+		a.EqualError(v.CheckSyntax(&s1), `["the panic tag must be applied to a string"]`)
+	}) && t.Run("lists the fields that were checked", func(t *testing.T) {
+		type address struct {
+			Street string `json:"street" validate:"required"`
+			City   string `json:"city"`
+		}
+		type s struct {
+			Name      string    `json:"name" validate:"required"`
+			Email     string    `json:"email" validate:"email"`
+			Nickname  string    `json:"nickname"`
+			Ignored   string    `json:"ignored" validate:"-"`
+			Addresses []address `json:"addresses" validate:"required"`
+			Wrong     string    `json:"wrong" valdiate:"required"`
+		}
+		v := New()
+		a := assert.New(t)
+		value := s{Name: "name", Email: "notAnEmail", Addresses: []address{{Street: "street"}, {}}}
+		checked, err := v.ValidateVerbose(&value)
+		a.Equal([]string{"name", "email", "addresses", "addresses[0].street", "addresses[1].street"}, checked)
+		a.EqualError(err, `["'email' must be a valid email address","'street' is required"]`)
+		a.Equal(v.Validate(&value), err)
+
+		// skipped fields are not checked
+		value.Email = ""
+		checked, err = New(&Config{SkipEmpty: true}).ValidateVerbose(&value)
+		a.Equal([]string{"name", "addresses", "addresses[0].street", "addresses[1].street"}, checked)
+		a.EqualError(err, `["'street' is required"]`)
+		checked, err = ValidateVerbose(&address{Street: "street"})
+		a.Equal([]string{"street"}, checked)
+		a.Nil(err)
+	}) && t.Run("validates maps", func(t *testing.T) {
+		rules := map[string]string{
+			"name":  "required & name",
+			"email": "empty | email",
+			"phone": "or:email",
+			"age":   "number:18",
+		}
+		var body map[string]interface{}
+		v := New()
+		a := assert.New(t)
+		a.NoError(json.Unmarshal([]byte(`{"name":"Mark","email":"mark@example.com","age":30}`), &body))
+		a.NoError(v.ValidateMap(body, rules))
+		a.NoError(json.Unmarshal([]byte(`{"name":"Mark","phone":"555-5555"}`), &body))
+		a.NoError(v.ValidateMap(body, rules))
+		body = nil
+		a.NoError(json.Unmarshal([]byte(`{"email":"","phone":null,"age":12}`), &body))
+		a.EqualError(v.ValidateMap(body, rules), `["'age' must be 18 or more","'name' is required","either 'phone' and/or 'email' must be set"]`)
+		a.EqualError(v.ValidateMap(map[string]interface{}{"name": "Mark", "email": "mark"}, rules), `["'email' must be a valid email address"]`)
+		a.Error(v.ValidateMap(body, map[string]string{"name": "required &"}))
+
+		// values of the wrong type are reported as errors of their keys instead of panicing
+		body = nil
+		a.NoError(json.Unmarshal([]byte(`{"name":"Mark","email":5}`), &body))
+		a.NotPanics(func() {
+			err := v.ValidateMap(body, map[string]string{"name": "required & name", "email": "required & email"})
+			a.EqualError(err, `["the email tag must be applied to a string"]`)
+			a.Equal("email", err.(FieldErrors)[0].(*FieldError).Path)
+		})
+	}) && t.Run("passes the parent type to rules", func(t *testing.T) {
+		type Parent struct {
+			Field string `json:"field" validate:"parent"`
+		}
+		type Root struct {
+			Parent Parent `json:"parent"`
+		}
+		var parentType, rootType reflect.Type
+		v := New(&Config{
+			ExtendDefaults: true,
+			Rules: Rules{
+				"parent": func(ps *RuleParams) error {
+					parentType, rootType = ps.ParentType, ps.RootType
+					if ps.ParentType.Name() != "Parent" {
+						return fmt.Errorf("'%s' has the wrong parent", ps.FieldName)
+					}
+					return nil
+				},
+			},
+		})
+		a := assert.New(t)
+		a.NoError(v.Validate(&Root{}))
+		a.Equal(reflect.TypeOf(Parent{}), parentType)
+		a.Equal(reflect.TypeOf(&Root{}), rootType)
+	}) && t.Run("skips optional rules", func(t *testing.T) {
+		type Struct struct {
+			Email string `json:"email" validate:"email?"`
+			Phone string `json:"phone" validate:"number:7,15? & or:Email"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.EqualError(v.Validate(&Struct{}), `["either 'phone' and/or 'email' must be set"]`)
+		a.NoError(v.Validate(&Struct{Email: "mark@example.com"}))
+		a.NoError(v.Validate(&Struct{Phone: "5555555"}))
+		a.EqualError(v.Validate(&Struct{Email: "mark", Phone: "555"}), `["'email' must be a valid email address","'phone' must be 7 to 15 digits"]`)
+	}) && t.Run("calls OnRuleExecuted", func(t *testing.T) {
+		type Struct struct {
+			Email string `json:"email" validate:"required & email"`
+			Phone string `json:"phone" validate:"number:7,15?"`
+			Name  string `json:"name" validate:"name | empty"`
+		}
+		var executed []string
+		v := New(&Config{
+			OnRuleExecuted: func(name string, field string, dur time.Duration, err error) {
+				executed = append(executed, fmt.Sprintf("%s %s %t", field, name, err == nil))
+				if dur < 0 {
+					t.Errorf("%s took %s", name, dur)
+				}
+			},
+		})
+		a := assert.New(t)
+		a.NoError(v.Validate(&Struct{Email: "mark@example.com"}))
+		a.Equal([]string{"email required true", "email email true", "name name false", "name empty true"}, executed)
+
+		// the hook is optional
+		a.NoError(New().Validate(&Struct{Email: "mark@example.com"}))
+	}) && t.Run("passes nil pointers as zero values", func(t *testing.T) {
+		type Nested struct {
+			Name string `json:"name" validate:"required"`
+		}
+		type Struct struct {
+			Name     *string `json:"name" validate:"required"`
+			Email    *string `json:"email" validate:"email"`
+			Optional *string `json:"optional" validate:"email?"`
+			Nested   *Nested `json:"nested"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.NoError(v.CheckSyntax(&Struct{}))
+		a.EqualError(v.Validate(&Struct{}), `["'name' is required","'email' must be a valid email address"]`)
+		name, email := "Mark", "mark@example.com"
+		a.NoError(v.Validate(&Struct{Name: &name, Email: &email}))
+		a.EqualError(v.Validate(&Struct{Name: &name, Email: &email, Nested: &Nested{}}), `["'name' is required"]`)
+	}) && t.Run("constructs field errors", func(t *testing.T) {
+		errNotFound := errors.New("not found")
+		err := NewFieldError("items[0].name", fmt.Errorf("'name' was %w", errNotFound))
+		a := assert.New(t)
+		a.Equal("items[0].name", err.Path)
+		a.EqualError(err, "'name' was not found")
+		a.True(errors.Is(err, errNotFound))
+		a.True(errors.Is(err, &FieldError{}))
+		a.False(errors.Is(err, errors.New("not found")))
+		var fe *FieldError
+		if a.True(errors.As(FieldErrors{err}, &fe)) {
+			a.Equal(err, fe)
+		}
+		var te *typeNameError
+		a.False(errors.As(err, &te))
+	}) && t.Run("walks multidimensional slices", func(t *testing.T) {
+		type Cell struct {
+			Value int `json:"value" validate:"number:0,9"`
+		}
+		type Board struct {
+			Grid  [][]Cell     `json:"grid"`
+			Cubes [][2][]*Cell `json:"cubes"`
+		}
+		b := Board{
+			Grid:  [][]Cell{{{1}, {2}, {3}}, {{4}, {5}, {10}}},
+			Cubes: [][2][]*Cell{{{{1}}, {{2}, {-1}}}},
+		}
+		var errs FieldErrors
+		a := assert.New(t)
+		if a.True(errors.As(New().Validate(&b), &errs)) && a.Len(errs, 2) {
+			a.Equal("grid[1][2].value", errs[0].(*FieldError).Path)
+			a.Equal("cubes[0][1][1].value", errs[1].(*FieldError).Path)
+		}
+	}) && t.Run("is configured with options", func(t *testing.T) {
+		type Struct struct {
+			Name  string `yaml:"name" v:"required & company"`
+			Email string `yaml:"email" v:"email"`
+		}
+		company := func(ps *RuleParams) error {
+			if ps.Field.String() != "Acme" {
+				return fmt.Errorf("'%s' must be Acme", ps.FieldName)
+			}
+			return nil
+		}
+		a := assert.New(t)
+
+		// the default rules are extended
+		v := NewWith(WithTag("v"), WithNameTag("yaml"), WithExtendedRules(Rules{"company": company}))
+		a.NoError(v.Validate(&Struct{Name: "Acme", Email: "mark@example.com"}))
+		a.EqualError(v.Validate(&Struct{Name: "Other", Email: "mark"}), `["'name' must be Acme","'email' must be a valid email address"]`)
+
+		// the default rules are replaced
+		v = NewWith(WithTag("v"), WithRules(Rules{"company": company, "required": Required}))
+		a.EqualError(v.CheckSyntax(&Struct{}), `["'email' is not a valid rule"]`)
+		a.Equal([]string{"company", "required"}, v.RuleNames())
+
+		// the rules of every option are merged together
+		v = NewWith(WithRules(Rules{"company": company}), WithRules(Rules{"required": Required}))
+		a.Equal([]string{"company", "required"}, v.RuleNames())
+		v = NewWith(WithRules(Rules{"company": company}), WithExtendedRules(Rules{"acme": company}))
+		a.Len(v.RuleNames(), len(New().RuleNames())+2)
+
+		// no options is the same as New
+		a.Equal(New().RuleNames(), NewWith().RuleNames())
+	}) && t.Run("resolves field references in params", func(t *testing.T) {
+		type Struct struct {
+			Password string `json:"password"`
+			Confirm  string `json:"confirm" validate:"eq:$Password"`
+			Digits   string `json:"digits" validate:"number:$Length,$Length"`
+			Length   int    `json:"length"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.NoError(v.Validate(&Struct{"secret", "secret", "1234", 4}))
+		a.EqualError(v.Validate(&Struct{"secret", "other", "123", 4}), `["'confirm' must equal 'secret'","'digits' must be 4 to 4 digits"]`)
+	}) && t.Run("resolves indexed field references in params", func(t *testing.T) {
+		type Item struct {
+			Price float64 `json:"price"`
+		}
+		type Struct struct {
+			Items    []*Item            `json:"items"`
+			Prices   map[string]float64 `json:"prices"`
+			Tiers    [2]int             `json:"tiers"`
+			Discount float64            `json:"discount" validate:"lte:$Items[0].Price"`
+			Budget   float64            `json:"budget" validate:"gte:$Prices[usd]"`
+			Count    int                `json:"count" validate:"lt:$Tiers[1]"`
+		}
+		v := New()
+		a := assert.New(t)
+		s := Struct{
+			Items:    []*Item{{Price: 10}, {Price: 20}},
+			Prices:   map[string]float64{"usd": 5},
+			Tiers:    [2]int{1, 10},
+			Discount: 10,
+			Budget:   5,
+			Count:    9,
+		}
+		a.NoError(v.CheckSyntax(&Struct{}))
+		a.NoError(v.Validate(&s))
+		s.Discount, s.Budget, s.Count = 15, 4, 10
+		a.EqualError(v.Validate(&s), `["'discount' must be 'price' or less","'budget' must be 'prices[usd]' or more","'count' must be less than 'tiers[1]'"]`)
+
+		// out of range indexes, missing keys and nil elements are the zero value
+		s = Struct{Items: []*Item{nil}, Discount: 1, Budget: -1, Count: -1}
+		a.EqualError(v.Validate(&s), `["'discount' must be 'price' or less","'budget' must be 'prices[usd]' or more"]`)
+		s.Items = nil
+		a.EqualError(v.Validate(&s), `["'discount' must be 'price' or less","'budget' must be 'prices[usd]' or more"]`)
+
+		// indexes that are out of range of an array or can't be used are syntax errors
+		var s1 struct {
+			Tiers [2]int `json:"tiers"`
+			Count int    `json:"count" validate:"lt:$Tiers[2]"`
+		}
+		var s2 struct {
+			Tiers []int `json:"tiers"`
+			Count int   `json:"count" validate:"lt:$Tiers[first]"`
+		}
+		var s3 struct {
+			Max   int `json:"max"`
+			Count int `json:"count" validate:"lt:$Max[0]"`
+		}
+		a.EqualError(v.CheckSyntax(&s1), `["index 2 is out of range of 'Tiers' ([2]int)"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'first' is not a valid index of 'Tiers'"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'Max' can not be indexed"]`)
+	}) && t.Run("validates a subset of fields", func(t *testing.T) {
+		type Address struct {
+			Street string `json:"street" validate:"required"`
+			City   string `json:"city" validate:"required"`
+		}
+		type Struct struct {
+			Name    string   `json:"name" validate:"required"`
+			Email   string   `json:"email" validate:"email"`
+			Age     int      `json:"age" validate:"number:18"`
+			Address *Address `json:"address" validate:"required"`
+		}
+		v := New()
+		a := assert.New(t)
+		s := Struct{Address: &Address{Street: "1 Main St"}}
+		a.EqualError(v.Validate(&s), `["'name' is required","'email' must be a valid email address","'age' must be 18 or more","'city' is required"]`)
+		a.EqualError(v.ValidateFields(&s, []string{"email"}), `["'email' must be a valid email address"]`)
+		a.EqualError(v.ValidateFields(&s, []string{"Name", "age"}), `["'name' is required","'age' must be 18 or more"]`)
+		a.EqualError(v.ValidateFields(&s, []string{"address.city"}), `["'city' is required"]`)
+		a.EqualError(v.ValidateFields(&s, []string{"Address"}), `["'city' is required"]`)
+		a.NoError(v.ValidateFields(&s, []string{"address.street"}))
+		a.EqualError(v.ValidateFields(&Struct{}, []string{"address"}), `["'address' is required"]`)
+		a.NoError(v.ValidateFields(&s, nil))
+		a.NoError(v.ValidateFields(&s, []string{"unknown"}))
+		s.Email = "mark@example.com"
+		a.NoError(v.ValidateFields(&[]Struct{s}, []string{"email"}))
+	}) && t.Run("stops at values that contain themselves", func(t *testing.T) {
+		type node struct {
+			Name     string                 `json:"name" validate:"required"`
+			Parent   *node                  `json:"parent"`
+			Children []*node                `json:"children"`
+			Meta     map[string]interface{} `json:"meta"`
+		}
+		v := New()
+		a := assert.New(t)
+
+		// a node that points to itself
+		n := &node{}
+		n.Parent = n
+		a.EqualError(v.Validate(n), `["'name' is required"]`)
+		a.NoError(v.CheckSyntax(n))
+
+		// a tree whose children point back to their parent
+		root := &node{Name: "root"}
+		root.Children = []*node{{Parent: root}, {Name: "child", Parent: root}}
+		root.Children[1].Children = root.Children
+		paths, err := v.ValidateVerbose(root)
+		a.EqualError(err, `["'name' is required"]`)
+		a.Equal([]string{"name", "children[0].name", "children[1].name"}, paths)
+
+		// a map that contains itself
+		m := map[string]interface{}{}
+		m["self"] = m
+		a.NoError(v.Validate(&node{Name: "map", Meta: m}))
+
+		// values that are shared but don't contain themselves are validated everywhere they are
+		shared := &node{}
+		a.EqualError(v.Validate(&node{Name: "a", Children: []*node{shared, shared}}), `["'name' is required","'name' is required"]`)
+	}) && t.Run("stops at the max depth", func(t *testing.T) {
+		type node struct {
+			Name string `json:"name" validate:"required"`
+			Next *node  `json:"next"`
+		}
+		list := &node{Name: "1", Next: &node{Name: "2", Next: &node{Next: &node{}}}}
+		a := assert.New(t)
+		a.EqualError(New().Validate(list), `["'name' is required","'name' is required"]`)
+		a.EqualError(New(&Config{MaxDepth: 2}).Validate(list), `["'name' is required","'next.next.next' is nested more than 2 levels deep"]`)
+		a.NoError(New(&Config{MaxDepth: 1}).Validate(&node{Name: "1", Next: &node{Name: "2"}}))
+	}) && t.Run("sorts errors by path", func(t *testing.T) {
+		type item struct {
+			Name  string `json:"name" validate:"required"`
+			Price int    `json:"price" validate:"number:1"`
+		}
+		type s struct {
+			Title string          `json:"title" validate:"required"`
+			Items map[string]item `json:"items"`
+		}
+		i := s{Items: map[string]item{"c": {Price: 1}, "a": {}, "b": {Name: "b"}}}
+		v := New(&Config{SortErrors: true})
+		a := assert.New(t)
+		for n := 0; n < 10; n++ {
+			errs := v.Validate(&i).(FieldErrors)
+			var paths []string
+			for _, err := range errs {
+				paths = append(paths, err.(*FieldError).Path)
+			}
+			a.Equal([]string{"items[a].name", "items[a].price", "items[b].price", "items[c].name", "title"}, paths)
+		}
+
+		// errors with the same path are sorted by their message, and errors without a path come first
+		errs := FieldErrors{NewFieldError("b", errors.New("2")), NewFieldError("b", errors.New("1")), errors.New("3"), NewFieldError("a", errors.New("4"))}
+		errs.Sort()
+		var messages []string
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		a.Equal([]string{"3", "4", "1", "2"}, messages)
+	}) && t.Run("formats errors", func(t *testing.T) {
+		type item struct {
+			Name string `json:"name" validate:"required"`
+		}
+		type s struct {
+			Email string `json:"email" validate:"email"`
+			Items []item `json:"items"`
+		}
+		v := New(&Config{ErrorFormatter: func(fe *FieldError) string {
+			return fe.Path + ": " + fe.Message.Error()
+		}})
+		a := assert.New(t)
+		err := v.Validate(&s{Items: []item{{Name: "a"}, {}}})
+		a.EqualError(err, `["email: 'email' must be a valid email address","items[1].name: 'name' is required"]`)
+		a.EqualError(err.(FieldErrors)[1], "items[1].name: 'name' is required")
+		bs, jsonErr := err.(FieldErrors).JSONObject()
+		a.NoError(jsonErr)
+		a.JSONEq(`{"email":"email: 'email' must be a valid email address","items[1].name":"items[1].name: 'name' is required"}`, string(bs))
+		a.EqualError(v.ValidateMap(map[string]interface{}{}, map[string]string{"name": "required"}), `["name: 'name' is required"]`)
+
+		// messages are rendered as they are by default
+		a.EqualError(New().Validate(&s{}), `["'email' must be a valid email address"]`)
+
+		// formatted messages are escaped in json
+		quoted := New(&Config{ErrorFormatter: func(fe *FieldError) string {
+			return `say "hi" to ` + fe.Path
+		}})
+		bs, jsonErr = json.Marshal(quoted.Validate(&s{}))
+		a.NoError(jsonErr)
+		a.Equal(`["say \"hi\" to email"]`, string(bs))
+	}) && t.Run("checks the presence of channels and funcs", func(t *testing.T) {
+		type s struct {
+			Done    chan struct{} `json:"done" validate:"required"`
+			OnSave  func() error  `json:"onSave" validate:"required"`
+			OnClose *func()       `json:"onClose" validate:"required"`
+			Events  <-chan int    `json:"events" validate:"empty | required"`
+			Cancel  func()        `json:"cancel" validate:"xor:Done"`
+		}
+		onClose := func() {}
+		v := New()
+		a := assert.New(t)
+		a.NoError(v.CheckSyntax(&s{}))
+		a.EqualError(v.Validate(&s{}), `["'done' is required","'onSave' is required","'onClose' is required","either 'cancel' or 'done' must be set"]`)
+		a.NoError(v.Validate(&s{Done: make(chan struct{}), OnSave: func() error { return nil }, OnClose: &onClose, Events: make(chan int)}))
+		a.EqualError(v.Validate(&s{Done: make(chan struct{}), OnSave: func() error { return nil }, OnClose: &onClose, Cancel: func() {}}), `["either 'cancel' or 'done' must be set"]`)
+		a.EqualError(New(&Config{SkipEmpty: true, CacheResults: true}).Validate(&s{}), `["'done' is required","'onSave' is required","'onClose' is required","either 'cancel' or 'done' must be set"]`)
+	}) && t.Run("streams errors", func(t *testing.T) {
+		type s struct {
+			Name  string `json:"name" validate:"required"`
+			Email string `json:"email" validate:"email"`
+		}
+		items := make([]s, 100)
+		items[50].Name = "set"
+		v := New()
+		a := assert.New(t)
+
+		// every error is streamed in the order it would have been returned in
+		var paths []string
+		var messages []string
+		v.ValidateStream(items, func(path string, err error) bool {
+			paths = append(paths, path)
+			messages = append(messages, err.Error())
+			return true
+		})
+		a.Len(paths, 199)
+		a.Equal([]string{"[0].name", "[0].email", "[1].name"}, paths[:3])
+		a.Equal([]string{"[50].email", "[51].name"}, paths[100:102])
+		a.Equal("'name' is required", messages[0])
+		var fe *FieldError
+		ValidateStream(&items[0], func(path string, err error) bool {
+			a.True(errors.As(err, &fe))
+			a.Equal(path, fe.Path)
+			return true
+		}, language.Spanish)
+		a.EqualError(fe, "'email' debe ser una dirección de correo electrónico válida")
+
+		// streaming stops as soon as the func returns false
+		var count int
+		v.ValidateStream(items, func(path string, err error) bool {
+			count++
+			return count < 3
+		})
+		a.Equal(3, count)
+		v.ValidateStream(&s{}, func(path string, err error) bool {
+			count++
+			return false
+		})
+		a.Equal(4, count)
+	}) && t.Run("compiles the tags of types", func(t *testing.T) {
+		type Item struct {
+			Name  string `json:"name" validate:"required & x"`
+			Price int    `json:"price" validate:"number:0"`
+		}
+		type Address struct {
+			City string `json:"city" validate:"required &"`
+		}
+		type Order struct {
+			ID       string             `json:"id" validate:"required"`
+			Items    []Item             `json:"items"`
+			Address  *Address           `json:"address"`
+			Gifts    map[string][]*Item `json:"gifts"`
+			Parent   *Order             `json:"parent"`
+			Internal struct {
+				Note string `validate:"y"`
+			} `json:"internal" validate:"nodive"`
+		}
+		type Valid struct {
+			Name  string `json:"name" validate:"required"`
+			Items []struct {
+				Name string `json:"name" validate:"required"`
+			} `json:"items"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.EqualError(v.Compile((*Order)(nil)), `["'x' is not a valid rule","bad '&' at 10 (line 1, column 11)"]`)
+		errs := v.Compile(Order{}, []Address{}).(FieldErrors)
+		a.Len(errs, 2)
+		a.Equal("items.name", errs[0].(*FieldError).Path)
+		a.Equal("address.city", errs[1].(*FieldError).Path)
+		a.NoError(v.Compile(Valid{}, (*Valid)(nil), nil, 1))
+		a.EqualError(Compile(&Item{}), `["'x' is not a valid rule"]`)
+	}) && t.Run("groups errors by field", func(t *testing.T) {
+		type address struct {
+			City string `json:"city" validate:"required"`
+		}
+		type s struct {
+			Name    string  `json:"name" validate:"required & letters"`
+			Email   string  `json:"email" validate:"email & runelen:5,50"`
+			Age     int     `json:"age"`
+			Address address `json:"address"`
+		}
+		v := New(&Config{ReportAll: true})
+		a := assert.New(t)
+		errs, err := v.ValidateGrouped(&s{Email: "x"})
+		a.EqualError(err, `["'name' is required","'name' can only contain letters and spaces","'email' must be a valid email address",`+
+			`"'email' must be 5 to 50 characters","'city' is required"]`)
+		a.Len(errs, 3)
+		a.EqualError(errs["name"], "'name' is required")
+		a.EqualError(errs["email"], "'email' must be a valid email address")
+		a.EqualError(errs["address.city"], "'city' is required")
+		errs, err = v.ValidateGrouped(&s{Name: "jane", Email: "jane@example.com", Address: address{"Austin"}})
+		a.Nil(errs)
+		a.Nil(err)
+		errs, err = ValidateGrouped(&s{Name: "jane", Email: "jane@example.com"}, language.Spanish)
+		a.Equal(map[string]error{"address.city": err.(FieldErrors)[0]}, errs)
+		a.EqualError(errs["address.city"], "'city' es obligatorio")
+	}) && t.Run("skips unexported fields", func(t *testing.T) {
+		type inner struct {
+			Name string `json:"name" validate:"required"`
+		}
+		type base struct {
+			ID string `json:"id" validate:"required"`
+		}
+		type s struct {
+			base
+			Name    string `json:"name" validate:"required"`
+			note    string
+			inner   inner
+			ignored string `validate:"-"`
+		}
+		type s1 struct {
+			Name string `json:"name" validate:"required"`
+			code string `validate:"required"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{base: base{"1"}, Name: "name"}))
+		a.EqualError(v.Validate(&s{}), `["'id' is required","'name' is required"]`)
+		a.Nil(v.CheckSyntax(&s{}))
+		a.Nil(v.Compile(&s{}))
+		a.NotPanics(func() {
+			a.EqualError(v.Validate(&s1{code: "code"}), `["'name' is required","'code' is unexported and can't be validated"]`)
+		})
+		a.EqualError(v.CheckSyntax(&s1{}), `["'code' is unexported and can't be validated"]`)
+		a.EqualError(v.Compile(&s1{}), `["'code' is unexported and can't be validated"]`)
+		a.NotPanics(func() {
+			a.Nil(New(&Config{CacheResults: true, SkipEmpty: true}).Validate(&s{base: base{"1"}, Name: "name", note: "note"}))
+		})
+
+		// the tags of unexported fields can be skipped instead
+		skip := New(&Config{SkipUnexportedTags: true})
+		a.NotPanics(func() {
+			a.EqualError(skip.Validate(&s1{code: "code"}), `["'name' is required"]`)
+			a.Nil(skip.Validate(&s1{Name: "name"}))
+		})
+		a.Nil(skip.CheckSyntax(&s1{}))
+		a.Nil(skip.Compile(&s1{}))
+		type s2 struct {
+			base `validate:"required"`
+		}
+		a.EqualError(v.Validate(&s2{base{"1"}}), `["'base' is unexported and can't be validated"]`)
+		a.EqualError(skip.Validate(&s2{}), `["'id' is required"]`)
+	}) && t.Run("translates messages", func(t *testing.T) {
+		type s struct {
+			Name  string `json:"name" validate:"required"`
+			Email string `json:"email" validate:"email"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.EqualError(v.Validate(&s{}), `["'name' is required","'email' must be a valid email address"]`)
+		a.EqualError(v.Validate(&s{}, language.Spanish), `["'name' es obligatorio","'email' debe ser una dirección de correo electrónico válida"]`)
+		a.EqualError(v.Validate(&s{}, language.MustParse("es-MX")), `["'name' es obligatorio","'email' debe ser una dirección de correo electrónico válida"]`)
+
+		// lists are joined together in the language of the message
+		type lists struct {
+			XOR  string `json:"xor" validate:"xor:OR,AND"`
+			OR   string `json:"or" validate:"or:XOR"`
+			AND  string `json:"and" validate:"and:XOR,OR"`
+			EQ   string `json:"eq" validate:"eq:one,two,three"`
+			Same string `json:"same" validate:"eq:one"`
+		}
+		a.EqualError(v.Validate(&lists{}), `["either 'xor', 'or' or 'and' must be set","either 'or' and/or 'xor' must be set",`+
+			`"'and', 'xor' and 'or' must be set","'eq' must equal 'one', 'two' or 'three'","'same' must equal 'one'"]`)
+		a.EqualError(v.Validate(&lists{}, language.Spanish), `["o bien 'xor', 'or' o 'and' debe estar establecido",`+
+			`"al menos uno de 'or' o 'xor' debe estar establecido","'and', 'xor' y 'or' deben estar establecidos",`+
+			`"'eq' debe ser igual a 'one', 'two' o 'three'","'same' debe ser igual a 'one'"]`)
+
+		// register a new language
+		RegisterMessages(language.French, map[string]string{
+			"required": "'%s' est obligatoire",
+		})
+		a.EqualError(v.Validate(&s{}, language.French), `["'name' est obligatoire","'email' must be a valid email address"]`)
+	}) && t.Run("uses the default language", func(t *testing.T) {
+		type s struct {
+			Name  string `json:"name" validate:"required"`
+			Email string `json:"email" validate:"email"`
+		}
+		v := New(&Config{DefaultLanguage: language.Spanish})
+		a := assert.New(t)
+		a.EqualError(v.Validate(&s{}), `["'name' es obligatorio","'email' debe ser una dirección de correo electrónico válida"]`)
+		a.EqualError(v.Validate(&s{}, language.English), `["'name' is required","'email' must be a valid email address"]`)
+		a.EqualError(v.ValidateFields(&s{}, []string{"name"}), `["'name' es obligatorio"]`)
+		a.EqualError(v.ValidateMap(map[string]interface{}{}, map[string]string{"name": "required"}), `["'name' es obligatorio"]`)
+		a.EqualError(NewWith(WithDefaultLanguage(language.Spanish)).Validate(&s{Name: "name"}),
+			`["'email' debe ser una dirección de correo electrónico válida"]`)
+	}); !pass {
+		t.Fatal("tests failed!")
+	}
+}
+
+type benchmarkStruct struct {
+	One   string  `json:"one" validate:"required"`
+	Two   string  `json:"two" validate:"required & letters"`
+	Three string  `json:"three" validate:"empty | letters"`
+	Four  string  `json:"four" validate:"empty | email"`
+	Five  int     `json:"five" validate:"number:1,10"`
+	Six   int     `json:"six" validate:"empty | number:1,10"`
+	Seven uint    `json:"seven" validate:"or:Eight,Nine"`
+	Eight uint    `json:"eight"`
+	Nine  uint    `json:"nine"`
+	Ten   float64 `json:"ten" validate:"eq:0,1,2"`
+}
+
+func BenchmarkValidate(b *testing.B) {
+	s := benchmarkStruct{One: "one", Two: "two", Five: 5, Seven: 7}
+	b.Run("cold", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			New().Validate(&s)
+		}
+	})
+	b.Run("warm", func(b *testing.B) {
+		v := New()
+		for i := 0; i < b.N; i++ {
+			v.Validate(&s)
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		v := New(&Config{CacheResults: true})
+		for i := 0; i < b.N; i++ {
+			v.Validate(&s)
+		}
+	})
+}
+
+// pointerStringer implements fmt.Stringer with a pointer receiver
+type pointerStringer struct{}
+
+func (*pointerStringer) String() string {
+	return "pointer"
+}
+
+func TestRules(t *testing.T) {
+	debug = verboseLogs
+	if pass := t.Run("required", func(t *testing.T) {
+		var s1 struct {
+			Field string `validate:"required"`
+		}
+		s2 := struct {
+			Field string `validate:"required"`
+		}{
+			Field: "populated",
+		}
+		v := New()
+		a := assert.New(t)
+		a.EqualError(v.Validate(&s1), `["'Field' is required"]`)
+		a.Nil(v.Validate(&s2))
+	}) && t.Run("required time", func(t *testing.T) {
+		type s struct {
+			Created time.Time  `json:"created" validate:"required"`
+			Updated *time.Time `json:"updated" validate:"required"`
+		}
+		now := time.Now()
+		v := New()
+		a := assert.New(t)
+		a.EqualError(v.Validate(&s{}), `["'created' is required","'updated' is required"]`)
+		a.EqualError(v.Validate(&s{Created: time.Time{}.Local(), Updated: &now}), `["'created' is required"]`)
+		a.EqualError(v.Validate(&s{Created: time.Time{}.In(time.FixedZone("EST", -5*60*60)), Updated: &now}), `["'created' is required"]`)
+		a.EqualError(v.Validate(&s{Created: now, Updated: &time.Time{}}), `["'updated' is required"]`)
+		a.Nil(v.Validate(&s{Created: now, Updated: &now}))
+		a.Nil(v.Validate(&s{Created: time.Unix(0, 0), Updated: &now}))
+	}) && t.Run("empty", func(t *testing.T) {
+		var s1 struct {
+			Field string `validate:"empty | fail"`
+		}
+		s2 := struct {
+			Field string `validate:"empty | fail"`
+		}{
+			Field: "populated",
+		}
+		v := New(&Config{
+			Rules: Rules{
+				"empty": Empty,
+				"fail": func(*RuleParams) error {
+					return errors.New("this will always fail")
+				},
+			},
+		})
+		a := assert.New(t)
+		a.Nil(v.Validate(&s1))
+		a.EqualError(v.Validate(&s2), `["this will always fail"]`)
+	}) && t.Run("email", func(t *testing.T) {
+		var s1 struct {
+			EmailAddress string `validate:"email"`
+		}
+		var s2 struct {
+			EmailAddress uint `validate:"email"`
+		}
+		v := New(&Config{
+			Rules: Rules{
+				"email": Email,
+			},
+		})
+		a := assert.New(t)
+
+		// empty emails fail
+		a.EqualError(v.Validate(&s1), `["'EmailAddress' must be a valid email address"]`)
+
+		// incorrect emails fail
+		s1.EmailAddress = "notAnEmail@"
+		a.EqualError(v.Validate(&s1), `["'EmailAddress' must be a valid email address"]`)
+
+		// empty emails fail
+		s1.EmailAddress = "hello@dealyze.com"
+		a.Nil(v.Validate(&s1))
+
+		// strict emails are within the length limits and don't have consecutive dots
+		type s3 struct {
+			EmailAddress string `json:"email" validate:"email:strict"`
+		}
+		local, domain := strings.Repeat("a", 64), strings.Repeat("b", 63)
+		a.Nil(v.Validate(&s3{"hello@dealyze.com"}))
+		a.Nil(v.Validate(&s3{local + "@" + domain + ".com"}))
+		a.EqualError(v.Validate(&s3{local + "a@dealyze.com"}), `["'email' must be a valid email address"]`)
+		s1.EmailAddress = local + "a@dealyze.com"
+		a.Nil(v.Validate(&s1))
+		long := local + "@" + strings.Repeat(domain+".", 3) + "com"
+		a.Len(long, 64+1+64*3+3)
+		a.EqualError(v.Validate(&s3{long}), `["'email' must be a valid email address"]`)
+		s1.EmailAddress = long
+		a.Nil(v.Validate(&s1))
+		a.EqualError(v.Validate(&s3{`"hello..world"@dealyze.com`}), `["'email' must be a valid email address"]`)
+		s1.EmailAddress = `"hello..world"@dealyze.com`
+		a.Nil(v.Validate(&s1))
+
+		// params can be quoted
+		type s5 struct {
+			EmailAddress string `json:"email" validate:"email:'strict'"`
+		}
+		a.Nil(v.Validate(&s5{"hello@dealyze.com"}))
+		a.EqualError(v.Validate(&s5{local + "a@dealyze.com"}), `["'email' must be a valid email address"]`)
+
+		// syntax check
+		var s4 struct {
+			EmailAddress string `validate:"email:loose"`
+		}
+		a.EqualError(v.CheckSyntax(&s2), `["the email tag must be applied to a string"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["'loose' is not a valid param for email"]`)
+	}) && t.Run("password", func(t *testing.T) {
+		var s1 struct {
+			Password string `validate:"password"`
+		}
+		var s2 struct {
+			Password []byte `validate:"password"`
+		}
+		v := New(&Config{
+			Rules: Rules{
+				"password": Password,
+			},
+		})
+		a := assert.New(t)
+
+		// empty passwords fail every criteria
+		a.EqualError(v.Validate(&s1), `["'Password' must be at least 6 characters long","'Password' must contain at least one number or special character (eg. @!#)"]`)
+
+		// weak passwords fail every criteria
+		s1.Password = "abc"
+		err := v.Validate(&s1)
+		a.EqualError(err, `["'Password' must be at least 6 characters long","'Password' must contain at least one number or special character (eg. @!#)"]`)
+		var errs FieldErrors
+		if a.True(errors.As(err, &errs)) && a.Len(errs, 2) {
+			a.Equal("Password", errs[0].(*FieldError).Path)
+			a.Equal("Password", errs[1].(*FieldError).Path)
+
+			// merging the errors flattens them without duplicating them
+			var merged FieldErrors
+			merged.Add(errs, errors.New("another error"))
+			a.Len(merged, 3)
+		}
+
+		// password without special characters fails
+		s1.Password = "notavalidpassword"
+		a.EqualError(v.Validate(&s1), `["'Password' must contain at least one number or special character (eg. @!#)"]`)
+
+		// password that is too short fails
+		s1.Password = "abc12"
+		a.EqualError(v.Validate(&s1), `["'Password' must be at least 6 characters long"]`)
+
+		// valid password succeeds
+		s1.Password = "abc123"
+		a.Nil(v.Validate(&s1))
+
+		// syntax check
+		a.EqualError(v.CheckSyntax(&s2), `["the password tag must be applied to a string"]`)
+	}) && t.Run("number", func(t *testing.T) {
+		var s1 struct {
+			Number string `validate:"number"`
+		}
+		var s2 struct {
+			Number string `validate:"number:2,4"`
+		}
+		var s3 struct {
+			Number int `validate:"number:2,4"`
+		}
+		var s4 struct {
+			Int     int     `validate:"number"`
+			Int8    int8    `validate:"number"`
+			Int16   int16   `validate:"number"`
+			Int32   int32   `validate:"number"`
+			Int64   int64   `validate:"number"`
+			Uint    uint    `validate:"number"`
+			Uint8   uint8   `validate:"number"`
+			Uint16  uint16  `validate:"number"`
+			Uint32  uint32  `validate:"number"`
+			Uint64  uint64  `validate:"number"`
+			Float32 float32 `validate:"number"`
+			Float64 float64 `validate:"number"`
+		}
+		v := New(&Config{
+			Rules: Rules{
+				"number": Number,
+			},
+		})
+		a := assert.New(t)
+
+		// empty strings fail
+		a.NotNil(v.Validate(&s1))
+
+		// incorrect string number fails
+		s1.Number = "not a number"
+		a.NotNil(v.Validate(&s1))
+
+		// strings that is only numbers works
+		s1.Number = "12345"
+		a.Nil(v.Validate(&s1))
+
+		// digit range
+		s2.Number = "0"
+		a.EqualError(v.Validate(&s2), `["'Number' must be 2 to 4 digits"]`)
+		s2.Number = "000"
+		a.Nil(v.Validate(&s2))
+		s2.Number = "00000"
+		a.EqualError(v.Validate(&s2), `["'Number' must be 2 to 4 digits"]`)
+
+		// int range
+		s3.Number = 1
+		a.EqualError(v.Validate(&s3), `["'Number' must be 2 to 4"]`)
+		s3.Number = 3
+		a.Nil(v.Validate(&s3))
+		s3.Number = 5
+		a.EqualError(v.Validate(&s3), `["'Number' must be 2 to 4"]`)
+
+		// all numbers are valid
+		a.Nil(v.Validate(&s4))
+
+	}) && t.Run("eq", func(t *testing.T) {
+		type s struct {
+			Uint   uint   `json:"a" validate:"eq:1,2,3"`
+			Int    int    `json:"b" validate:"eq:1,2,3"`
+			String string `json:"c" validate:"eq:1,2,3"`
+		}
+		s1 := s{
+			1, 2, "3",
+		}
+		var s2 s
+		var s3 struct {
+			Uint uint `json:"a" validate:"eq"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s1))
+		a.EqualError(v.Validate(&s2), `["'a' must equal '1', '2' or '3'","'b' must equal '1', '2' or '3'","'c' must equal '1', '2' or '3'"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["eq requires at least one parameter"]`)
+
+		// quoted params are compared without their quotes
+		type s4 struct {
+			Greeting string `json:"greeting" validate:"eq:'hello world',hi"`
+		}
+		a.Nil(v.Validate(&s4{"hello world"}))
+		a.EqualError(v.Validate(&s4{"'hello world'"}), `["'greeting' must equal 'hello world' or 'hi'"]`)
+		a.Nil(EQ(&RuleParams{Field: reflect.ValueOf("hello world"), Params: []string{`'hello world'`}}))
+
+		// quotes and other html characters in params are rendered as they are
+		type quoted struct {
+			A string `validate:"eq:'a\"b','<c>'"`
+		}
+		err := v.Validate(&quoted{})
+		a.EqualError(err, `["'A' must equal 'a\"b' or '<c>'"]`)
+		a.EqualError(err.(FieldErrors)[0], `'A' must equal 'a"b' or '<c>'`)
+		bs, jsonErr := json.Marshal(err)
+		a.NoError(jsonErr)
+		a.Equal(`["'A' must equal 'a\"b' or '\u003cc\u003e'"]`, string(bs))
+		a.Nil(v.Validate(&quoted{`a"b`}))
+
+		// text marshalers are compared by their text, and times by their instant
+		type s5 struct {
+			Language language.Tag `json:"language" validate:"eq:en,es"`
+			Date     time.Time    `json:"date" validate:"eq:2024-01-02"`
+			Time     time.Time    `json:"time" validate:"eq:2024-01-02T15:04:05Z"`
+			Pointer  *time.Time   `json:"pointer" validate:"eq:2024-01-02"`
+		}
+		date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		moment := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		a.Nil(v.Validate(&s5{language.Spanish, date, moment, &date}))
+		a.Nil(v.Validate(&s5{language.English, date, moment.In(time.FixedZone("EST", -5*60*60)), &date}))
+		a.EqualError(v.Validate(&s5{language.French, date.Add(time.Hour), moment.Add(time.Second), nil}), `["'language' must equal 'en' or 'es'",`+
+			`"'date' must equal '2024-01-02'","'time' must equal '2024-01-02T15:04:05Z'","'pointer' must equal '2024-01-02'"]`)
+	}) && t.Run("xor", func(t *testing.T) {
+		type s struct {
+			Uint   uint   `json:"a" validate:"xor:Int,String"`
+			Int    int    `json:"b"`
+			String string `json:"c"`
+		}
+		s1 := s{
+			Uint: 1,
+		}
+		s2 := s{
+			Int: 1,
+		}
+		s3 := s{
+			String: "1",
+		}
+		s4 := s{
+			Uint:   1,
+			Int:    1,
+			String: "1",
+		}
+		s5 := s{}
+		var s6 struct {
+			Uint uint `json:"a" validate:"xor:Int,String"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s1))
+		a.Nil(v.Validate(&s2))
+		a.Nil(v.Validate(&s3))
+		a.EqualError(v.Validate(&s4), `["either 'a', 'b' or 'c' must be set"]`)
+		a.EqualError(v.Validate(&s5), `["either 'a', 'b' or 'c' must be set"]`)
+		a.EqualError(v.CheckSyntax(&s6), `["'.Int' is not a valid field"]`)
+	}) && t.Run("or", func(t *testing.T) {
+		type s struct {
+			Uint   uint   `json:"a" validate:"or:Int,String"`
+			Int    int    `json:"b"`
+			String string `json:"c"`
+		}
+		s1 := s{
+			Uint: 1,
+		}
+		s2 := s{
+			Int: 1,
+		}
+		s3 := s{
+			String: "1",
+		}
+		s4 := s{
+			Uint:   1,
+			Int:    1,
+			String: "1",
+		}
+		s5 := s{}
+		var s6 struct {
+			Uint uint `json:"a" validate:"or:Int,String"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s1))
+		a.Nil(v.Validate(&s2))
+		a.Nil(v.Validate(&s3))
+		a.Nil(v.Validate(&s4))
+		a.EqualError(v.Validate(&s5), `["either 'a', 'b' and/or 'c' must be set"]`)
+		a.EqualError(v.CheckSyntax(&s6), `["'.Int' is not a valid field"]`)
+	}) && t.Run("and", func(t *testing.T) {
+		type s struct {
+			Uint   uint   `json:"a" validate:"and:Int,String"`
+			Int    int    `json:"b"`
+			String string `json:"c"`
+		}
+		s1 := s{
+			Uint: 1,
+		}
+		s2 := s{
+			Int: 1,
+		}
+		s3 := s{
+			String: "1",
+		}
+		s4 := s{
+			Uint:   1,
+			Int:    1,
+			String: "1",
+		}
+		s5 := s{}
+		var s6 struct {
+			Uint uint `json:"a" validate:"or:Int,String"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.EqualError(v.Validate(&s1), `["'a', 'b' and 'c' must be set"]`)
+		a.EqualError(v.Validate(&s2), `["'a', 'b' and 'c' must be set"]`)
+		a.EqualError(v.Validate(&s3), `["'a', 'b' and 'c' must be set"]`)
+		a.Nil(v.Validate(&s4))
+		a.EqualError(v.Validate(&s5), `["'a', 'b' and 'c' must be set"]`)
+		a.EqualError(v.CheckSyntax(&s6), `["'.Int' is not a valid field"]`)
+	}) && t.Run("contrast", func(t *testing.T) {
+		var s1 struct {
+			Color string `json:"color" validate:"contrast:'#ffffff','4.5'"`
+		}
+		var s2 struct {
+			Color int `json:"color" validate:"contrast:'#ffffff','4.5'"`
+		}
+		var s3 struct {
+			Color string `json:"color" validate:"contrast:'white','4.5'"`
+		}
+		v := New()
+		a := assert.New(t)
+
+		// black on white passes
+		s1.Color = "#000"
+		a.Nil(v.Validate(&s1))
+
+		// light gray on white fails
+		s1.Color = "#777777"
+		a.EqualError(v.Validate(&s1), `["'color' must have a contrast ratio of at least 4.5 against #ffffff"]`)
+
+		// invalid colors fail
+		s1.Color = "black"
+		a.EqualError(v.Validate(&s1), `["'color' must have a contrast ratio of at least 4.5 against #ffffff"]`)
+
+		// syntax check
+		a.EqualError(v.CheckSyntax(&s2), `["the contrast tag must be applied to a string"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["contrast requires a valid background color, got 'white'"]`)
+	}) && t.Run("divisible_by", func(t *testing.T) {
+		type s struct {
+			Quantity int  `json:"quantity" validate:"divisible_by:PackSize"`
+			PackSize uint `json:"packSize"`
+		}
+		var s1 struct {
+			Quantity int    `json:"quantity" validate:"divisible_by:PackSize"`
+			PackSize string `json:"packSize"`
+		}
+		var s2 struct {
+			Quantity int `json:"quantity" validate:"divisible_by:Size"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{Quantity: 12, PackSize: 6}))
+		a.EqualError(v.Validate(&s{Quantity: 13, PackSize: 6}), `["'quantity' must be a multiple of 'packSize'"]`)
+		a.EqualError(v.Validate(&s{Quantity: 12}), `["'quantity' must be a multiple of 'packSize'"]`)
+		a.Nil(v.Validate(&s{Quantity: -12, PackSize: 6}))
+		a.EqualError(v.Validate(&s{Quantity: 1, PackSize: math.MaxUint64}), `["'quantity' must be a multiple of 'packSize'"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'PackSize' must be an integer to be used by divisible_by"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'.Size' is not a valid field"]`)
+	}) && t.Run("dockertag", func(t *testing.T) {
+		type s struct {
+			Image string `json:"image" validate:"dockertag"`
+		}
+		var s1 struct {
+			Image []byte `json:"image" validate:"dockertag"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"nginx:1.21"}))
+		a.Nil(v.Validate(&s{"library/nginx"}))
+		a.Nil(v.Validate(&s{"localhost:5000/app:latest"}))
+		a.Nil(v.Validate(&s{"gcr.io/proj/app@sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}))
+		a.EqualError(v.Validate(&s{"UPPER/name"}), `["'image' must be a valid image reference"]`)
+		a.EqualError(v.Validate(&s{"nginx:"}), `["'image' must be a valid image reference"]`)
+		a.EqualError(v.Validate(&s{"app@sha256:abc"}), `["'image' must be a valid image reference"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the dockertag tag must be applied to a string"]`)
+	}) && t.Run("wholeseconds", func(t *testing.T) {
+		type s struct {
+			CreatedAt time.Time `json:"createdAt" validate:"wholeseconds"`
+		}
+		var s1 struct {
+			CreatedAt int64 `json:"createdAt" validate:"wholeseconds"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{time.Date(2020, 1, 1, 12, 30, 15, 0, time.UTC)}))
+		a.EqualError(v.Validate(&s{time.Date(2020, 1, 1, 12, 30, 15, 500, time.UTC)}), `["'createdAt' must not have sub-second precision"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the wholeseconds tag must be applied to a time.Time"]`)
+	}) && t.Run("in", func(t *testing.T) {
+		RegisterSet("roles", []string{"admin", "user"})
+		type s struct {
+			Role string `json:"role" validate:"in:roles"`
+		}
+		var s1 struct {
+			Role string `json:"role" validate:"in:unknown"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"admin"}))
+		a.EqualError(v.Validate(&s{"ADMIN"}), `["'role' must be one of the allowed roles"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'unknown' is not a registered set"]`)
+	}) && t.Run("in_fold", func(t *testing.T) {
+		RegisterSet("roles", []string{"admin", "user"})
+		type s struct {
+			Role string `json:"role" validate:"in_fold:roles"`
+		}
+		var s1 struct {
+			Role string `json:"role" validate:"in_fold:unknown"`
+		}
+		var s2 struct {
+			Role int `json:"role" validate:"in_fold:roles"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"ADMIN"}))
+		a.Nil(v.Validate(&s{"User"}))
+		a.EqualError(v.Validate(&s{"guest"}), `["'role' must be one of the allowed roles"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'unknown' is not a registered set"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["the in_fold tag must be applied to a string"]`)
+	}) && t.Run("distinct", func(t *testing.T) {
+		type item struct {
+			ID   int
+			Name string
+		}
+		type s struct {
+			Tags  []string `json:"tags" validate:"distinct"`
+			Items [2]item  `json:"items" validate:"distinct"`
+		}
+		var s1 struct {
+			Tags string `json:"tags" validate:"distinct"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{Tags: []string{"a", "b", "c"}, Items: [2]item{{1, "a"}, {2, "a"}}}))
+		a.EqualError(v.Validate(&s{Tags: []string{"a", "b", "a"}, Items: [2]item{{1, "a"}, {1, "a"}}}), `["'tags' must not contain duplicates","'items' must not contain duplicates"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the distinct tag must be applied to a slice or an array"]`)
+	}) && t.Run("degrees", func(t *testing.T) {
+		type s struct {
+			Heading float64 `json:"heading" validate:"degrees"`
+			Bearing float32 `json:"bearing" validate:"degrees:wrap"`
+		}
+		var s1 struct {
+			Heading int `json:"heading" validate:"degrees"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{Heading: 0, Bearing: -720}))
+		a.Nil(v.Validate(&s{Heading: 360, Bearing: 1080}))
+		a.EqualError(v.Validate(&s{Heading: -0.5}), `["'heading' must be between 0 and 360 degrees"]`)
+		a.EqualError(v.Validate(&s{Heading: 360.5, Bearing: float32(math.Inf(1))}), `["'heading' must be between 0 and 360 degrees","'bearing' must be between 0 and 360 degrees"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the degrees tag must be applied to a float"]`)
+	}) && t.Run("radians", func(t *testing.T) {
+		type s struct {
+			Angle float64 `json:"angle" validate:"radians"`
+			Phase float64 `json:"phase" validate:"radians:wrap"`
+		}
+		var s1 struct {
+			Angle float64 `json:"angle" validate:"radians:clamp"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{Angle: 0, Phase: -10}))
+		a.Nil(v.Validate(&s{Angle: 2 * math.Pi, Phase: 10}))
+		a.EqualError(v.Validate(&s{Angle: 2*math.Pi + 0.001}), `["'angle' must be between 0 and 2π radians"]`)
+		a.EqualError(v.Validate(&s{Angle: -0.001, Phase: math.NaN()}), `["'angle' must be between 0 and 2π radians","'phase' must be between 0 and 2π radians"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'clamp' is not a valid param for radians"]`)
+		var quoted struct {
+			Phase float64 `json:"phase" validate:"radians:'wrap'"`
+		}
+		quoted.Phase = -10
+		a.Nil(v.Validate(&quoted))
+	}) && t.Run("each", func(t *testing.T) {
+		type s struct {
+			Emails []string          `json:"emails" validate:"each:'email'"`
+			Names  map[string]string `json:"names" validate:"each:'empty | letters'"`
+		}
+		var s1 struct {
+			Emails string `json:"emails" validate:"each:'email'"`
+		}
+		var s2 struct {
+			Emails []string `json:"emails" validate:"each:'email &'"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{Emails: []string{"a@b.com", "c@d.com"}, Names: map[string]string{"first": "First", "middle": ""}}))
+
+		// every invalid element is reported with its index
+		err := v.Validate(&s{Emails: []string{"a@b.com", "notAnEmail", "c@"}, Names: map[string]string{"last": "L4st"}})
+		a.EqualError(err, `["'emails[1]' must be a valid email address","'emails[2]' must be a valid email address","'names[last]' can only contain letters and spaces"]`)
+		var errs FieldErrors
+		if a.True(errors.As(err, &errs)) && a.Len(errs, 3) {
+			a.Equal("emails[1]", errs[0].(*FieldError).Path)
+			a.Equal("emails[2]", errs[1].(*FieldError).Path)
+			a.Equal("names[last]", errs[2].(*FieldError).Path)
+		}
+
+		// syntax check
+		a.EqualError(v.CheckSyntax(&s1), `["the each tag must be applied to a slice, an array or a map"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["bad '&' at 7 (line 1, column 8)"]`)
+
+		// nil pointers are the zero value of the type they point to
+		type s3 struct {
+			Emails []*string `json:"emails" validate:"each:'email'"`
+			Names  []*string `json:"names" validate:"each:'empty | letters'"`
+		}
+		email := "a@b.com"
+		a.NotPanics(func() {
+			a.EqualError(v.Validate(&s3{Emails: []*string{&email, nil}, Names: []*string{nil}}), `["'emails[1]' must be a valid email address"]`)
+		})
+
+		// the default rules are used when the rule is called directly
+		a.Nil(Each(&RuleParams{Field: reflect.ValueOf([]int{1}), Params: []string{"required"}}))
+		a.EqualError(Each(&RuleParams{Field: reflect.ValueOf([]int{1, 0}), FieldName: "ints", Params: []string{"required"}}), `["'ints[1]' is required"]`)
+		a.PanicsWithError("'x' is not a valid rule", func() {
+			Each(&RuleParams{Field: reflect.ValueOf([]int{1}), Params: []string{"x"}})
+		})
+	}) && t.Run("enum_for", func(t *testing.T) {
+		RegisterSetMap("states", map[string][]string{
+			"US": {"CA", "NY"},
+			"MX": {"JAL", "NLE"},
+		})
+		type s struct {
+			Country string `json:"country"`
+			State   string `json:"state" validate:"enum_for:Country,states"`
+		}
+		var s1 struct {
+			Country string `json:"country"`
+			State   string `json:"state" validate:"enum_for:Country,provinces"`
+		}
+		var s2 struct {
+			State string `json:"state" validate:"enum_for:Country,states"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"US", "NY"}))
+		a.Nil(v.Validate(&s{"MX", "JAL"}))
+		a.EqualError(v.Validate(&s{"US", "JAL"}), `["'state' is not valid for the selected 'country'"]`)
+		a.EqualError(v.Validate(&s{"CA", "ON"}), `["'state' is not valid for the selected 'country'"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'provinces' is not a registered set map"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'.Country' is not a valid field"]`)
+	}) && t.Run("colwidth", func(t *testing.T) {
+		type s struct {
+			Label string `json:"label" validate:"colwidth:1,4"`
+		}
+		var s1 struct {
+			Label []rune `json:"label" validate:"colwidth:1,4"`
+		}
+		var s2 struct {
+			Label string `json:"label" validate:"colwidth:4,1"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"abcd"}))
+		a.Nil(v.Validate(&s{"日本"}))
+		a.EqualError(v.Validate(&s{""}), `["'label' must be between 1 and 4 columns wide"]`)
+		a.EqualError(v.Validate(&s{"abcde"}), `["'label' must be between 1 and 4 columns wide"]`)
+
+		// only 3 runes, but 6 columns
+		a.EqualError(v.Validate(&s{"日本語"}), `["'label' must be between 1 and 4 columns wide"]`)
+
+		// params can be quoted
+		type s3 struct {
+			Label string `json:"label" validate:"colwidth:'1','4'"`
+		}
+		a.Nil(v.Validate(&s3{"abcd"}))
+		a.EqualError(v.Validate(&s3{"abcde"}), `["'label' must be between 1 and 4 columns wide"]`)
+
+		// syntax check
+		a.EqualError(v.CheckSyntax(&s1), `["the colwidth tag must be applied to a string"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["colwidth requires a numeric max that is at least the min, got '1'"]`)
+	}) && t.Run("enum", func(t *testing.T) {
+		RegisterEnum("role", []string{"admin", "user"})
+		type s struct {
+			Role string `json:"role" validate:"enum:role"`
+		}
+		type s1 struct {
+			Level enumLevel `json:"level" validate:"enum:level"`
+		}
+		var s2 struct {
+			Role string `json:"role" validate:"enum:unknown"`
+		}
+		RegisterEnum("level", []string{"low", "high"})
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"admin"}))
+		a.Nil(v.Validate(&s{"user"}))
+		a.EqualError(v.Validate(&s{"guest"}), `["'role' must be a valid role"]`)
+		a.Nil(v.Validate(&s1{enumLevelHigh}))
+		a.EqualError(v.Validate(&s1{enumLevel(7)}), `["'level' must be a valid level"]`)
+		a.Nil(v.CheckSyntax(&s{}))
+		a.EqualError(v.CheckSyntax(&s2), `["'unknown' is not a registered enum"]`)
+
+		// enums and sets share a registry
+		RegisterSet("plan", []string{"free", "pro"})
+		type s3 struct {
+			Plan string `json:"plan" validate:"enum:plan"`
+			Role string `json:"role" validate:"in:role"`
+		}
+		a.Nil(v.Validate(&s3{"pro", "admin"}))
+		a.EqualError(v.Validate(&s3{"team", "guest"}), `["'plan' must be a valid plan","'role' must be one of the allowed role"]`)
+	}) && t.Run("eqfield", func(t *testing.T) {
+		type s struct {
+			Password        string `json:"password"`
+			PasswordConfirm string `json:"passwordConfirm" validate:"eqfield:Password"`
+		}
+		type s1 struct {
+			Max   int64 `json:"max"`
+			Limit uint8 `json:"limit" validate:"eqfield:Max"`
+		}
+		var s2 struct {
+			PasswordConfirm string `json:"passwordConfirm" validate:"eqfield:Password"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"Secret123", "Secret123"}))
+		a.EqualError(v.Validate(&s{"Secret123", "secret123"}), `["'passwordConfirm' must match 'password'"]`)
+		a.Nil(v.Validate(&s1{10, 10}))
+		a.EqualError(v.Validate(&s1{256, 0}), `["'limit' must match 'max'"]`)
+
+		// unsigned values above math.MaxInt64 don't wrap around
+		type s3 struct {
+			Max   int64  `json:"max"`
+			Limit uint64 `json:"limit" validate:"eqfield:Max"`
+		}
+		a.EqualError(v.Validate(&s3{-1, math.MaxUint64}), `["'limit' must match 'max'"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'.Password' is not a valid field"]`)
+	}) && t.Run("nefield", func(t *testing.T) {
+		type s struct {
+			OldPassword string `json:"oldPassword"`
+			NewPassword string `json:"newPassword" validate:"nefield:OldPassword"`
+		}
+		type s1 struct {
+			Min float32 `json:"min"`
+			Max float64 `json:"max" validate:"nefield:Min"`
+		}
+		type s2 struct {
+			From int  `json:"from"`
+			To   uint `json:"to" validate:"nefield:From"`
 		}
-		var s2 s
 		var s3 struct {
-			Uint uint `json:"a" validate:"eq"`
+			NewPassword string `json:"newPassword" validate:"nefield:OldPassword"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"Secret123", "Secret456"}))
+		a.EqualError(v.Validate(&s{"Secret123", "Secret123"}), `["'newPassword' must be different from 'oldPassword'"]`)
+		a.Nil(v.Validate(&s1{1.5, 2.5}))
+		a.EqualError(v.Validate(&s1{1.5, 1.5}), `["'max' must be different from 'min'"]`)
+		a.Nil(v.Validate(&s2{-1, 1}))
+		a.EqualError(v.Validate(&s2{3, 3}), `["'to' must be different from 'from'"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'.OldPassword' is not a valid field"]`)
+	}) && t.Run("glob", func(t *testing.T) {
+		type s struct {
+			Filename string `json:"filename" validate:"glob:'*.png'"`
+		}
+		type s1 struct {
+			Filename string `json:"filename" validate:"glob:'img_[0-9]*.{png,jpg}'"`
+		}
+		var s2 struct {
+			Filename string `json:"filename" validate:"glob:'[a-'"`
+		}
+		var s3 struct {
+			Filename []byte `json:"filename" validate:"glob:'*.png'"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"logo.png"}))
+		a.EqualError(v.Validate(&s{"logo.jpg"}), `["'filename' does not match the required pattern"]`)
+		a.EqualError(v.Validate(&s{"images/logo.png"}), `["'filename' does not match the required pattern"]`)
+		a.Nil(v.Validate(&s1{"img_1.{png,jpg}"}))
+		a.EqualError(v.Validate(&s1{"img_1.png"}), `["'filename' does not match the required pattern"]`)
+		a.Nil(v.CheckSyntax(&s{}))
+		a.EqualError(v.CheckSyntax(&s2), `["'[a-' is not a valid glob pattern"]`)
+
+		// quotes can be escaped inside of the pattern
+		type s4 struct {
+			Title string `json:"title" validate:"glob:'it\\'s *'"`
+		}
+		a.Nil(v.Validate(&s4{"it's a title"}))
+		a.EqualError(v.Validate(&s4{"its a title"}), `["'title' does not match the required pattern"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the glob tag must be applied to a string"]`)
+	}) && t.Run("hex", func(t *testing.T) {
+		type s struct {
+			Signature string `json:"signature" validate:"hex"`
+		}
+		var s1 struct {
+			Signature []byte `json:"signature" validate:"hex"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"deadBEEF0123"}))
+		a.EqualError(v.Validate(&s{"xyz0"}), `["'signature' must be valid hex"]`)
+		a.EqualError(v.Validate(&s{"abc"}), `["'signature' must be valid hex"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the hex tag must be applied to a string"]`)
+	}) && t.Run("base64", func(t *testing.T) {
+		type s struct {
+			Data string `json:"data" validate:"base64"`
+		}
+		type s1 struct {
+			Data string `json:"data" validate:"base64:url"`
+		}
+		var s2 struct {
+			Data string `json:"data" validate:"base64:raw"`
+		}
+		var s3 struct {
+			Data int `json:"data" validate:"base64"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"+/8="}))
+		a.EqualError(v.Validate(&s{"-_8="}), `["'data' must be valid base64"]`)
+		a.EqualError(v.Validate(&s{"+/8"}), `["'data' must be valid base64"]`)
+		a.Nil(v.Validate(&s1{"-_8="}))
+		var quoted struct {
+			Data string `json:"data" validate:"base64:'url'"`
+		}
+		quoted.Data = "-_8="
+		a.Nil(v.Validate(&quoted))
+		a.EqualError(v.Validate(&s1{"+/8="}), `["'data' must be valid base64"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'raw' is not a valid param for base64"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the base64 tag must be applied to a string"]`)
+	}) && t.Run("base32", func(t *testing.T) {
+		type s struct {
+			Token string `json:"token" validate:"base32"`
+		}
+		type s1 struct {
+			Token string `json:"token" validate:"base32:hex"`
+		}
+		var s2 struct {
+			Token string `json:"token" validate:"base32:url"`
+		}
+		var s3 struct {
+			Token int `json:"token" validate:"base32"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"MZXW6YTBOI======"}))
+		a.EqualError(v.Validate(&s{"MZXW6YTBOI"}), `["'token' must be valid base32"]`)
+		a.EqualError(v.Validate(&s{"mzxw6ytboi======"}), `["'token' must be valid base32"]`)
+		a.EqualError(v.Validate(&s{"MZXW6YTB0I======"}), `["'token' must be valid base32"]`)
+		a.Nil(v.Validate(&s1{"CPNMUOJ1E8======"}))
+		a.EqualError(v.Validate(&s1{"CPNMUOJ1EZ======"}), `["'token' must be valid base32"]`)
+		var quoted struct {
+			Token string `json:"token" validate:"base32:'hex'"`
+		}
+		quoted.Token = "CPNMUOJ1E8======"
+		a.Nil(v.Validate(&quoted))
+		a.EqualError(v.CheckSyntax(&s2), `["'url' is not a valid param for base32"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the base32 tag must be applied to a string"]`)
+	}) && t.Run("base58", func(t *testing.T) {
+		type s struct {
+			Address string `json:"address" validate:"base58"`
+		}
+		var s1 struct {
+			Address []byte `json:"address" validate:"base58"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"}))
+		a.Nil(v.Validate(&s{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"}))
+		for _, address := range []string{"0A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNO", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNI", "1A1zP1eP5QGefi2DMPTfTl5SLmv7DivfNa", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfN+"} {
+			a.EqualError(v.Validate(&s{address}), `["'address' must be valid base58"]`, address)
+		}
+		a.EqualError(v.CheckSyntax(&s1), `["the base58 tag must be applied to a string"]`)
+	}) && t.Run("json", func(t *testing.T) {
+		type s struct {
+			Metadata string `json:"metadata" validate:"json"`
+		}
+		var s1 struct {
+			Metadata []byte `json:"metadata" validate:"json"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{`{"key": "value", "count": 1}`}))
+		a.Nil(v.Validate(&s{`[1, "two", null]`}))
+		a.EqualError(v.Validate(&s{""}), `["'metadata' must be valid json"]`)
+		a.EqualError(v.Validate(&s{`{"key": }`}), `["'metadata' must be valid json"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the json tag must be applied to a string"]`)
+	}) && t.Run("startswith", func(t *testing.T) {
+		type s struct {
+			URL string `json:"url" validate:"startswith:'https://','http://'"`
+		}
+		type s1 struct {
+			Code string `json:"code" validate:"startswith:US"`
+		}
+		type s2 struct {
+			Code string `json:"code" validate:"startswith:US,CA,MX"`
+		}
+		var s3 struct {
+			Code int `json:"code" validate:"startswith:US"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"https://example.com"}))
+		a.Nil(v.Validate(&s{"http://example.com"}))
+		a.EqualError(v.Validate(&s{"ftp://example.com"}), `["'url' must start with https:// or http://"]`)
+		a.Nil(v.Validate(&s1{"US-123"}))
+		a.EqualError(v.Validate(&s1{"CA-123"}), `["'code' must start with US"]`)
+		a.Nil(v.Validate(&s2{"MX-123"}))
+		a.EqualError(v.Validate(&s2{"GB-123"}), `["'code' must start with US, CA or MX"]`)
+		a.EqualError(v.Validate(&s2{"GB-123"}, language.Spanish), `["'code' debe empezar con US, CA o MX"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the startswith tag must be applied to a string"]`)
+	}) && t.Run("endswith", func(t *testing.T) {
+		type s struct {
+			Email string `json:"email" validate:"endswith:'.com','.org'"`
+		}
+		type s1 struct {
+			File string `json:"file" validate:"endswith:'.go'"`
+		}
+		var s2 struct {
+			File string `json:"file" validate:"endswith"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"a@b.com"}))
+		a.Nil(v.Validate(&s{"a@b.org"}))
+		a.EqualError(v.Validate(&s{"a@b.net"}), `["'email' must end with .com or .org"]`)
+		a.Nil(v.Validate(&s1{"main.go"}))
+		a.EqualError(v.Validate(&s1{"main.rs"}), `["'file' must end with .go"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["endswith requires at least one parameter"]`)
+	}) && t.Run("lowercase", func(t *testing.T) {
+		type s struct {
+			Username string `json:"username" validate:"lowercase"`
+		}
+		var s1 struct {
+			Username []byte `json:"username" validate:"lowercase"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"john_doe-42"}))
+		a.Nil(v.Validate(&s{"jérôme"}))
+		a.EqualError(v.Validate(&s{"John_Doe"}), `["'username' must be lowercase"]`)
+		a.EqualError(v.Validate(&s{"jÉrôme"}), `["'username' must be lowercase"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the lowercase tag must be applied to a string"]`)
+	}) && t.Run("uppercase", func(t *testing.T) {
+		type s struct {
+			Code string `json:"code" validate:"uppercase"`
+		}
+		var s1 struct {
+			Code int `json:"code" validate:"uppercase"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"USD-42"}))
+		a.EqualError(v.Validate(&s{"Usd"}), `["'code' must be uppercase"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the uppercase tag must be applied to a string"]`)
+	}) && t.Run("notblank", func(t *testing.T) {
+		type s struct {
+			Name string `json:"name" validate:"notblank"`
+		}
+		type s1 struct {
+			Count int `json:"count" validate:"notblank"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"x"}))
+		a.EqualError(v.Validate(&s{""}), `["'name' must not be blank"]`)
+		a.EqualError(v.Validate(&s{"   "}), `["'name' must not be blank"]`)
+		a.EqualError(v.Validate(&s{"\t\n"}), `["'name' must not be blank"]`)
+		a.Nil(v.Validate(&s1{1}))
+		a.EqualError(v.Validate(&s1{}), `["'count' must not be blank"]`)
+
+		// blank fields are not skipped
+		a.EqualError(New(&Config{SkipEmpty: true}).Validate(&s{}), `["'name' must not be blank"]`)
+	}) && t.Run("root fields", func(t *testing.T) {
+		type item struct {
+			Price    float64 `json:"price"`
+			Currency string  `json:"currency" validate:"eqfield:root.Currency"`
+		}
+		type invoice struct {
+			Currency string `json:"invoiceCurrency"`
+			Items    []item `json:"items"`
+		}
+		type settings struct {
+			Currency string `json:"currency"`
+		}
+		type order struct {
+			Settings *settings `json:"settings"`
+			Item     item      `json:"item" validate:"-"`
+			Currency string    `json:"currency" validate:"eqfield:Settings.Currency"`
+		}
+		var s struct {
+			Item item `json:"item"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&invoice{"USD", []item{{1, "USD"}, {2, "USD"}}}))
+		err := v.Validate(&invoice{"USD", []item{{1, "USD"}, {2, "EUR"}}})
+		a.EqualError(err, `["'currency' must match 'invoiceCurrency'"]`)
+		var errs FieldErrors
+		if a.True(errors.As(err, &errs)) && a.Len(errs, 1) {
+			a.Equal("items[1].currency", errs[0].(*FieldError).Path)
+		}
+
+		// nested fields are separated by dots
+		a.Nil(v.Validate(&order{Settings: &settings{"USD"}, Currency: "USD"}))
+		a.EqualError(v.Validate(&order{Settings: &settings{"USD"}, Currency: "EUR"}), `["'currency' must match 'currency'"]`)
+		a.EqualError(v.CheckSyntax(&s), `["'.Currency' is not a valid field"]`)
+	}) && t.Run("strongpassword", func(t *testing.T) {
+		type s struct {
+			Password string `json:"password" validate:"strongpassword:8,1,1,1"`
+		}
+		type s1 struct {
+			Password string `json:"password" validate:"strongpassword:10,2,0,3,0"`
+		}
+		var s2 struct {
+			Password string `json:"password" validate:"strongpassword:eight"`
+		}
+		var s3 struct {
+			Password []byte `json:"password" validate:"strongpassword"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"Str0ng!Pass"}))
+		a.EqualError(v.Validate(&s{"S0!p"}), `["'password' must be at least 8 characters long"]`)
+		a.EqualError(v.Validate(&s{"str0ng!pass"}), `["'password' must contain 1 or more uppercase letters"]`)
+		a.EqualError(v.Validate(&s{"STR0NG!PASS"}), `["'password' must contain 1 or more lowercase letters"]`)
+		a.EqualError(v.Validate(&s{"Strong!Pass"}), `["'password' must contain 1 or more digits"]`)
+		a.EqualError(v.Validate(&s{"Str0ngPass"}), `["'password' must contain 1 or more symbols (eg. @!#)"]`)
+		a.Nil(v.Validate(&s1{"ABCDEF1234"}))
+		a.EqualError(v.Validate(&s1{"Abcdef1234"}), `["'password' must contain 2 or more uppercase letters"]`)
+		a.EqualError(v.Validate(&s1{"ABcdef12cd"}), `["'password' must contain 3 or more digits"]`)
+		a.EqualError(v.Validate(&s1{"ABcdef12cd"}, language.Spanish), `["'password' debe contener 3 o más dígitos"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["strongpassword requires numeric params, got 'eight'"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the strongpassword tag must be applied to a string"]`)
+	}) && t.Run("latitude", func(t *testing.T) {
+		type s struct {
+			Lat float64 `json:"lat" validate:"latitude"`
+		}
+		type s1 struct {
+			Lat string `json:"lat" validate:"latitude"`
+		}
+		var s2 struct {
+			Lat bool `json:"lat" validate:"latitude"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{40.7128}))
+		a.Nil(v.Validate(&s{-90}))
+		a.EqualError(v.Validate(&s{90.5}), `["'lat' must be a valid latitude"]`)
+		a.Nil(v.Validate(&s1{"-33.8688"}))
+		a.EqualError(v.Validate(&s1{"-91"}), `["'lat' must be a valid latitude"]`)
+		a.EqualError(v.Validate(&s1{"north"}), `["'lat' must be a valid latitude"]`)
+		a.EqualError(v.Validate(&s1{"NaN"}), `["'lat' must be a valid latitude"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["the latitude tag must be applied to a number or a string"]`)
+	}) && t.Run("longitude", func(t *testing.T) {
+		type s struct {
+			Lng int `json:"lng" validate:"longitude"`
+		}
+		type s1 struct {
+			Lng string `json:"lng" validate:"longitude"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{-180}))
+		a.Nil(v.Validate(&s{151}))
+		a.EqualError(v.Validate(&s{181}), `["'lng' must be a valid longitude"]`)
+		a.Nil(v.Validate(&s1{"-74.0060"}))
+		a.EqualError(v.Validate(&s1{"200.1"}), `["'lng' must be a valid longitude"]`)
+		a.EqualError(v.Validate(&s1{"west"}), `["'lng' must be a valid longitude"]`)
+	}) && t.Run("hexcolor", func(t *testing.T) {
+		type s struct {
+			Color string `json:"color" validate:"hexcolor"`
+		}
+		var s1 struct {
+			Color int `json:"color" validate:"hexcolor"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"#fff"}))
+		a.Nil(v.Validate(&s{"#1A2b3C"}))
+		a.Nil(v.Validate(&s{"#1a2b3c80"}))
+		a.EqualError(v.Validate(&s{"1a2b3c"}), `["'color' must be a valid hex color"]`)
+		a.EqualError(v.Validate(&s{"#1a2b"}), `["'color' must be a valid hex color"]`)
+		a.EqualError(v.Validate(&s{"#1a2b3c8"}), `["'color' must be a valid hex color"]`)
+		a.EqualError(v.Validate(&s{"#ggg"}), `["'color' must be a valid hex color"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the hexcolor tag must be applied to a string"]`)
+	}) && t.Run("currency", func(t *testing.T) {
+		type s struct {
+			Currency string `json:"currency" validate:"currency"`
+		}
+		type s1 struct {
+			Currency string `json:"currency" validate:"currency:fold"`
+		}
+		var s2 struct {
+			Currency string `json:"currency" validate:"currency:lower"`
+		}
+		var s3 struct {
+			Currency int `json:"currency" validate:"currency"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"USD"}))
+		a.Nil(v.Validate(&s{"EUR"}))
+		a.EqualError(v.Validate(&s{"ABC"}), `["'currency' must be a valid currency code"]`)
+		a.EqualError(v.Validate(&s{"usd"}), `["'currency' must be a valid currency code"]`)
+		a.Nil(v.Validate(&s1{"usd"}))
+		a.Nil(v.Validate(&s1{"Jpy"}))
+		a.EqualError(v.Validate(&s1{"abc"}), `["'currency' must be a valid currency code"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'lower' is not a valid param for currency"]`)
+		var quoted struct {
+			Currency string `json:"currency" validate:"currency:'fold'"`
+		}
+		quoted.Currency = "usd"
+		a.Nil(v.Validate(&quoted))
+		a.EqualError(v.CheckSyntax(&s3), `["the currency tag must be applied to a string"]`)
+	}) && t.Run("country", func(t *testing.T) {
+		type s struct {
+			Country string `json:"country" validate:"country"`
+		}
+		type s1 struct {
+			Country string `json:"country" validate:"country:alpha3"`
+		}
+		var s2 struct {
+			Country string `json:"country" validate:"country:alpha4"`
+		}
+		var s3 struct {
+			Country int `json:"country" validate:"country"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"US"}))
+		a.Nil(v.Validate(&s{"DE"}))
+		a.EqualError(v.Validate(&s{"USA"}), `["'country' must be a valid ISO country code"]`)
+		a.EqualError(v.Validate(&s{"XX"}), `["'country' must be a valid ISO country code"]`)
+		a.Nil(v.Validate(&s1{"USA"}))
+		a.Nil(v.Validate(&s1{"ZWE"}))
+		a.EqualError(v.Validate(&s1{"US"}), `["'country' must be a valid ISO country code"]`)
+		a.EqualError(v.Validate(&s1{"usa"}), `["'country' must be a valid ISO country code"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'alpha4' is not a valid param for country"]`)
+		var quoted struct {
+			Country string `json:"country" validate:"country:'alpha3'"`
+		}
+		quoted.Country = "USA"
+		a.Nil(v.Validate(&quoted))
+		a.EqualError(v.CheckSyntax(&s3), `["the country tag must be applied to a string"]`)
+	}) && t.Run("semver", func(t *testing.T) {
+		type s struct {
+			Version string `json:"version" validate:"semver"`
+		}
+		type s1 struct {
+			Version string `json:"version" validate:"semver:'>=1.2.0','<2.0.0'"`
+		}
+		type s2 struct {
+			Version string `json:"version" validate:"semver:'>1.0.0-alpha.1'"`
+		}
+		var s3 struct {
+			Version string `json:"version" validate:"semver:'>=1.2'"`
+		}
+		var s4 struct {
+			Version int `json:"version" validate:"semver"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"1.2.3"}))
+		a.Nil(v.Validate(&s{"0.0.0"}))
+		a.Nil(v.Validate(&s{"1.0.0-alpha.1"}))
+		a.Nil(v.Validate(&s{"1.0.0-0.3.7+build.5"}))
+		a.EqualError(v.Validate(&s{"1.2"}), `["'version' must be a valid semantic version"]`)
+		a.EqualError(v.Validate(&s{"v1.2.3"}), `["'version' must be a valid semantic version"]`)
+		a.EqualError(v.Validate(&s{"01.2.3"}), `["'version' must be a valid semantic version"]`)
+		a.EqualError(v.Validate(&s{"1.2.3-"}), `["'version' must be a valid semantic version"]`)
+		a.Nil(v.Validate(&s1{"1.2.0"}))
+		a.Nil(v.Validate(&s1{"1.10.0"}))
+		a.Nil(v.Validate(&s1{"2.0.0-rc.1"}))
+		a.EqualError(v.Validate(&s1{"1.1.9"}).(FieldErrors)[0], `'version' must be a version >=1.2.0`)
+		a.EqualError(v.Validate(&s1{"1.2.0-rc.1"}).(FieldErrors)[0], `'version' must be a version >=1.2.0`)
+		a.EqualError(v.Validate(&s1{"2.0.0"}).(FieldErrors)[0], `'version' must be a version <2.0.0`)
+		a.Nil(v.Validate(&s2{"1.0.0-alpha.beta"}))
+		a.Nil(v.Validate(&s2{"1.0.0-alpha.10"}))
+		a.Nil(v.Validate(&s2{"1.0.0"}))
+		a.EqualError(v.Validate(&s2{"1.0.0-alpha"}).(FieldErrors)[0], `'version' must be a version >1.0.0-alpha.1`)
+		a.EqualError(v.Validate(&s2{"1.0.0-alpha.1+build"}).(FieldErrors)[0], `'version' must be a version >1.0.0-alpha.1`)
+		a.EqualError(v.CheckSyntax(&s3).(FieldErrors)[0], `'>=1.2' is not a valid constraint for semver`)
+		a.EqualError(v.CheckSyntax(&s4), `["the semver tag must be applied to a string"]`)
+	}) && t.Run("sorted", func(t *testing.T) {
+		type s struct {
+			Scores []int `json:"scores" validate:"sorted"`
+		}
+		type s1 struct {
+			Scores [3]float64 `json:"scores" validate:"sorted:desc"`
+		}
+		type s2 struct {
+			Names []string `json:"names" validate:"sorted:asc"`
+		}
+		var s3 struct {
+			Scores []int `json:"scores" validate:"sorted:up"`
+		}
+		var s4 struct {
+			Scores []bool `json:"scores" validate:"sorted"`
+		}
+		var s5 struct {
+			Scores int `json:"scores" validate:"sorted"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{}))
+		a.Nil(v.Validate(&s{[]int{1}}))
+		a.Nil(v.Validate(&s{[]int{1, 2, 2, 3}}))
+		a.EqualError(v.Validate(&s{[]int{1, 3, 2}}), `["'scores' must be sorted"]`)
+		a.EqualError(v.Validate(&s{[]int{3, 2, 1}}), `["'scores' must be sorted"]`)
+		a.Nil(v.Validate(&s1{[3]float64{3.5, 2, 2}}))
+		a.EqualError(v.Validate(&s1{[3]float64{1, 2, 3}}), `["'scores' must be sorted"]`)
+		var quoted struct {
+			Scores []int `json:"scores" validate:"sorted:'desc'"`
+		}
+		quoted.Scores = []int{3, 2, 1}
+		a.Nil(v.Validate(&quoted))
+		a.Nil(v.Validate(&s2{[]string{"a", "b", "c"}}))
+		a.EqualError(v.Validate(&s2{[]string{"b", "a"}}), `["'names' must be sorted"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'up' is not a valid param for sorted"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["the sorted tag must be applied to a slice or an array of integers, floats or strings"]`)
+		a.EqualError(v.CheckSyntax(&s5), `["the sorted tag must be applied to a slice or an array"]`)
+	}) && t.Run("format", func(t *testing.T) {
+		type s struct {
+			Phone   string `json:"phone" validate:"format:e164"`
+			Created string `json:"created" validate:"format:rfc3339"`
+			IP      string `json:"ip" validate:"format:ipv4"`
+		}
+		type s1 struct {
+			Code string `json:"code" validate:"format:zip"`
+		}
+		var s2 struct {
+			Code string `json:"code" validate:"format:unknown"`
+		}
+		var s3 struct {
+			Code string `json:"code" validate:"format"`
+		}
+		var s4 struct {
+			Code int `json:"code" validate:"format:zip"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"+15551234567", "2006-01-02T15:04:05Z", "192.168.0.1"}))
+		a.Nil(v.Validate(&s{"+442071234567", "2006-01-02T15:04:05.999-07:00", "8.8.8.8"}))
+		a.EqualError(v.Validate(&s{"5551234567", "2006-01-02", "::ffff:192.168.0.1"}),
+			`["'phone' is not a valid e164","'created' is not a valid rfc3339","'ip' is not a valid ipv4"]`)
+
+		// custom formats
+		RegisterFormat("zip", func(s string) bool {
+			_, err := strconv.Atoi(s)
+			return len(s) == 5 && err == nil
+		})
+		a.Nil(v.Validate(&s1{"90210"}))
+		a.EqualError(v.Validate(&s1{"9021"}), `["'code' is not a valid zip"]`)
+
+		// the format name can be quoted
+		type s5 struct {
+			Code string `json:"code" validate:"format:'zip'"`
+		}
+		a.Nil(v.Validate(&s5{"90210"}))
+		a.EqualError(v.Validate(&s5{"9021"}), `["'code' is not a valid zip"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'unknown' is not a valid format"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["format requires exactly one format name"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["the format tag must be applied to a string"]`)
+	}) && t.Run("runelen", func(t *testing.T) {
+		type s struct {
+			Name string `json:"name" validate:"runelen:1,5"`
+		}
+		type s1 struct {
+			Name string `json:"name" validate:"runelen:0,3"`
+		}
+		type s2 struct {
+			Name string `json:"name" validate:"runelen:3"`
+		}
+		var s3 struct {
+			Name string `json:"name" validate:"runelen:one"`
+		}
+		var s4 struct {
+			Name string `json:"name" validate:"runelen"`
+		}
+		var s5 struct {
+			Name []byte `json:"name" validate:"runelen:1"`
+		}
+		v := New()
+		a := assert.New(t)
+
+		// multibyte characters are counted once, even though they take up more than one byte
+		a.Equal(6, len("héllo"))
+		a.Nil(v.Validate(&s{"hello"}))
+		a.Nil(v.Validate(&s{"héllo"}))
+		a.Nil(v.Validate(&s{"😀😀😀😀😀"}))
+		a.Equal(20, len("😀😀😀😀😀"))
+		a.EqualError(v.Validate(&s{""}), `["'name' must be 1 to 5 characters"]`)
+		a.EqualError(v.Validate(&s{"héllo!"}), `["'name' must be 1 to 5 characters"]`)
+		a.Nil(v.Validate(&s1{"ñoñ"}))
+		a.EqualError(v.Validate(&s1{"ñoño"}), `["'name' must be 0 to 3 characters"]`)
+		a.Nil(v.Validate(&s2{"añ😀"}))
+		a.EqualError(v.Validate(&s2{"😀😀"}), `["'name' must be 3 characters or more"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'one' is not a valid length for runelen"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["runelen requires a min and an optional max"]`)
+		a.EqualError(v.CheckSyntax(&s5), `["the runelen tag must be applied to a string"]`)
+	}) && t.Run("inlist", func(t *testing.T) {
+		type s struct {
+			Username string `json:"username" validate:"inlist:usernames"`
+		}
+		var s1 struct {
+			Username string `json:"username" validate:"inlist:unknown"`
+		}
+		var s2 struct {
+			Username string `json:"username" validate:"inlist:broken"`
+		}
+		var s3 struct {
+			Username int `json:"username" validate:"inlist:usernames"`
+		}
+		RegisterList("usernames", strings.NewReader("mark\n  jane \n\nbob\n"))
+		RegisterList("broken", errReader{errors.New("closed")})
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"mark"}))
+		a.Nil(v.Validate(&s{"jane"}))
+		a.Nil(v.Validate(&s{"bob"}))
+		a.EqualError(v.Validate(&s{"alice"}), `["'username' is not allowed"]`)
+		a.EqualError(v.Validate(&s{""}), `["'username' is not allowed"]`)
+
+		// the list name can be quoted
+		type s4 struct {
+			Username string `json:"username" validate:"inlist:'usernames'"`
+		}
+		a.Nil(v.Validate(&s4{"mark"}))
+		a.EqualError(v.Validate(&s4{"alice"}), `["'username' is not allowed"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'unknown' is not a registered list"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["the 'broken' list could not be read: closed"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the inlist tag must be applied to a string"]`)
+	}) && t.Run("notin", func(t *testing.T) {
+		type s struct {
+			Username string `json:"username" validate:"notin:admin,root,system"`
+		}
+		type s1 struct {
+			ID   int     `json:"id" validate:"notin:0,1"`
+			Rate float64 `json:"rate" validate:"notin:0.5"`
+			Port uint16  `json:"port" validate:"notin:22,80"`
+		}
+		var s2 struct {
+			Username string `json:"username" validate:"notin"`
 		}
 		v := New()
 		a := assert.New(t)
-		a.Nil(v.Validate(&s1))
-		a.EqualError(v.Validate(&s2), `["'a' must equal '1', '2' or '3'","'b' must equal '1', '2' or '3'","'c' must equal '1', '2' or '3'"]`)
-		a.EqualError(v.CheckSyntax(&s3), "eq requires at least one parameter")
-	}) && t.Run("xor", func(t *testing.T) {
+		a.Nil(v.Validate(&s{"mark"}))
+		a.EqualError(v.Validate(&s{"root"}), `["'username' must not be one of 'admin', 'root' or 'system'"]`)
+		a.EqualError(v.Validate(&s{"root"}, language.Spanish), `["'username' no debe ser ninguno de 'admin', 'root' o 'system'"]`)
+		a.Nil(v.Validate(&s1{2, 0.25, 443}))
+		a.EqualError(v.Validate(&s1{1, 0.5, 80}), `["'id' must not be one of '0' or '1'","'rate' must not equal '0.5'","'port' must not be one of '22' or '80'"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["notin requires at least one parameter"]`)
+	}) && t.Run("lt, lte, gt and gte", func(t *testing.T) {
 		type s struct {
-			Uint   uint   `json:"a" validate:"xor:Int,String"`
-			Int    int    `json:"b"`
-			String string `json:"c"`
+			LT  int     `json:"lt" validate:"lt:10"`
+			LTE uint    `json:"lte" validate:"lte:10"`
+			GT  float64 `json:"gt" validate:"gt:0.5"`
+			GTE int64   `json:"gte" validate:"gte:-1"`
 		}
-		s1 := s{
-			Uint: 1,
+		type s1 struct {
+			Count int      `json:"count" validate:"lt:$Max & gte:$Min"`
+			Max   uint8    `json:"max"`
+			Min   *float64 `json:"min"`
 		}
-		s2 := s{
-			Int: 1,
+		type s2 struct {
+			End   time.Time `json:"end" validate:"gt:$Start & lte:'2030-01-01T00:00:00Z'"`
+			Start time.Time `json:"start"`
 		}
-		s3 := s{
-			String: "1",
+		var s3 struct {
+			Count int `json:"count" validate:"lt:$Unknown"`
 		}
-		s4 := s{
-			Uint:   1,
-			Int:    1,
-			String: "1",
+		var s4 struct {
+			Count int    `json:"count" validate:"lt:$Name"`
+			Name  string `json:"name"`
+		}
+		var s5 struct {
+			Name string `json:"name" validate:"lt:10"`
 		}
-		s5 := s{}
 		var s6 struct {
-			Uint uint `json:"a" validate:"xor:Int,String"`
+			Count int `json:"count" validate:"lt:ten"`
+		}
+		var s7 struct {
+			At time.Time `json:"at" validate:"lt:'yesterday'"`
 		}
 		v := New()
 		a := assert.New(t)
-		a.Nil(v.Validate(&s1))
-		a.Nil(v.Validate(&s2))
-		a.Nil(v.Validate(&s3))
-		a.EqualError(v.Validate(&s4), `["either 'a', 'b' or 'c' must be set"]`)
-		a.EqualError(v.Validate(&s5), `["either 'a', 'b' or 'c' must be set"]`)
-		a.EqualError(v.CheckSyntax(&s6), "'.Int' is not a valid field")
-	}) && t.Run("or", func(t *testing.T) {
+		a.Nil(v.Validate(&s{9, 10, 0.75, -1}))
+		a.EqualError(v.Validate(&s{10, 11, 0.5, -2}), `["'lt' must be less than 10","'lte' must be 10 or less","'gt' must be greater than 0.5","'gte' must be -1 or more"]`)
+		min := 2.5
+		a.Nil(v.Validate(&s1{Count: 3, Max: 4, Min: &min}))
+		a.Nil(v.Validate(&s1{Count: 0, Max: 1}))
+		a.EqualError(v.Validate(&s1{Count: 4, Max: 4}), `["'count' must be less than 'max'"]`)
+		a.EqualError(v.Validate(&s1{Count: 2, Max: 4, Min: &min}), `["'count' must be 'min' or more"]`)
+		start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		a.Nil(v.Validate(&s2{start.Add(time.Hour), start}))
+		a.EqualError(v.Validate(&s2{start, start}), `["'end' must be greater than 'start'"]`)
+		a.EqualError(v.Validate(&s2{start.AddDate(20, 0, 0), start}), `["'end' must be 2030-01-01T00:00:00Z or less"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'.Unknown' is not a valid field"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["'Name' can't be compared to 'count'"]`)
+		a.EqualError(v.CheckSyntax(&s5), `["the lt tag must be applied to a number or a time"]`)
+		a.EqualError(v.CheckSyntax(&s6), `["'ten' is not a valid number for lt"]`)
+		a.EqualError(v.CheckSyntax(&s7), `["'yesterday' is not a valid RFC3339 time for lt"]`)
+	}) && t.Run("normalized", func(t *testing.T) {
 		type s struct {
-			Uint   uint   `json:"a" validate:"or:Int,String"`
-			Int    int    `json:"b"`
-			String string `json:"c"`
+			Name string `json:"name" validate:"normalized"`
 		}
-		s1 := s{
-			Uint: 1,
+		type s1 struct {
+			Name string `json:"name" validate:"normalized:nfd"`
 		}
-		s2 := s{
-			Int: 1,
+		type s2 struct {
+			Name string `json:"name" validate:"normalized:nfkc"`
 		}
-		s3 := s{
-			String: "1",
+		var s3 struct {
+			Name string `json:"name" validate:"normalized:nfx"`
 		}
-		s4 := s{
-			Uint:   1,
-			Int:    1,
-			String: "1",
+		var s4 struct {
+			Name []rune `json:"name" validate:"normalized"`
 		}
-		s5 := s{}
-		var s6 struct {
-			Uint uint `json:"a" validate:"or:Int,String"`
+		composed, decomposed := "caf\u00e9", "cafe\u0301"
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"cafe"}))
+		a.Nil(v.Validate(&s{composed}))
+		a.EqualError(v.Validate(&s{decomposed}), `["'name' must be in normalized form"]`)
+		a.Nil(v.Validate(&s1{decomposed}))
+		a.EqualError(v.Validate(&s1{composed}), `["'name' must be in normalized form"]`)
+		a.Nil(v.Validate(&s2{composed}))
+		a.EqualError(v.Validate(&s2{"\ufb01le"}), `["'name' must be in normalized form"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'nfx' is not a valid normalization form"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["the normalized tag must be applied to a string"]`)
+	}) && t.Run("duration", func(t *testing.T) {
+		type s struct {
+			Timeout string `json:"timeout" validate:"duration"`
+		}
+		type s1 struct {
+			Timeout string `json:"timeout" validate:"duration:1s,1h"`
+		}
+		type s2 struct {
+			Timeout time.Duration `json:"timeout" validate:"duration:100ms"`
+		}
+		var s3 struct {
+			Timeout string `json:"timeout" validate:"duration:soon"`
+		}
+		var s4 struct {
+			Timeout int `json:"timeout" validate:"duration"`
 		}
 		v := New()
 		a := assert.New(t)
-		a.Nil(v.Validate(&s1))
-		a.Nil(v.Validate(&s2))
-		a.Nil(v.Validate(&s3))
-		a.Nil(v.Validate(&s4))
-		a.EqualError(v.Validate(&s5), `["either 'a', 'b' and/or 'c' must be set"]`)
-		a.EqualError(v.CheckSyntax(&s6), "'.Int' is not a valid field")
-	}) && t.Run("and", func(t *testing.T) {
+		a.Nil(v.Validate(&s{"1h30m"}))
+		a.Nil(v.Validate(&s{"-1.5s"}))
+		a.Nil(v.Validate(&s{"0"}))
+		a.EqualError(v.Validate(&s{""}), `["'timeout' must be a valid duration"]`)
+		a.EqualError(v.Validate(&s{"1 hour"}), `["'timeout' must be a valid duration"]`)
+		a.EqualError(v.Validate(&s{"10"}), `["'timeout' must be a valid duration"]`)
+		a.Nil(v.Validate(&s1{"1s"}))
+		a.Nil(v.Validate(&s1{"59m59s"}))
+		a.Nil(v.Validate(&s1{"1h"}))
+		a.EqualError(v.Validate(&s1{"999ms"}), `["'timeout' must be between 1s and 1h"]`)
+		a.EqualError(v.Validate(&s1{"1h0m1s"}), `["'timeout' must be between 1s and 1h"]`)
+		a.EqualError(v.Validate(&s1{"soon"}), `["'timeout' must be a valid duration"]`)
+		a.Nil(v.Validate(&s2{}))
+		a.Nil(v.Validate(&s2{time.Second}))
+		a.EqualError(v.Validate(&s2{time.Millisecond}), `["'timeout' must be at least 100ms"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'soon' is not a valid duration for duration"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["the duration tag must be applied to a string or a time.Duration"]`)
+	}) && t.Run("ext", func(t *testing.T) {
 		type s struct {
-			Uint   uint   `json:"a" validate:"and:Int,String"`
-			Int    int    `json:"b"`
-			String string `json:"c"`
+			Avatar string `json:"avatar" validate:"ext:.jpg,.png,.gif"`
 		}
-		s1 := s{
-			Uint: 1,
+		type s1 struct {
+			Backup string `json:"backup" validate:"ext:tar.gz"`
 		}
-		s2 := s{
-			Int: 1,
+		var s2 struct {
+			Avatar string `json:"avatar" validate:"ext"`
 		}
-		s3 := s{
-			String: "1",
+		var s3 struct {
+			Avatar []byte `json:"avatar" validate:"ext:.jpg"`
 		}
-		s4 := s{
-			Uint:   1,
-			Int:    1,
-			String: "1",
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"me.jpg"}))
+		a.Nil(v.Validate(&s{"/images/me.PNG"}))
+		a.Nil(v.Validate(&s{"C:\\images\\me.gif"}))
+		a.EqualError(v.Validate(&s{"me.jpeg"}), `["'avatar' must have extension .jpg, .png or .gif"]`)
+		a.EqualError(v.Validate(&s{"me"}), `["'avatar' must have extension .jpg, .png or .gif"]`)
+		a.EqualError(v.Validate(&s{"jpg"}), `["'avatar' must have extension .jpg, .png or .gif"]`)
+		a.EqualError(v.Validate(&s{".jpg"}), `["'avatar' must have extension .jpg, .png or .gif"]`)
+		a.Nil(v.Validate(&s1{"backup.tar.gz"}))
+		a.EqualError(v.Validate(&s1{"backup.gz"}), `["'backup' must have extension tar.gz"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["ext requires at least one parameter"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the ext tag must be applied to a string"]`)
+	}) && t.Run("iban", func(t *testing.T) {
+		type s struct {
+			IBAN string `json:"iban" validate:"iban"`
+		}
+		var s1 struct {
+			IBAN []byte `json:"iban" validate:"iban"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"GB82WEST12345698765432"}))
+		a.Nil(v.Validate(&s{"GB82 WEST 1234 5698 7654 32"}))
+		a.Nil(v.Validate(&s{"DE89 3704 0044 0532 0130 00"}))
+		a.EqualError(v.Validate(&s{"GB83 WEST 1234 5698 7654 32"}), `["'iban' must be a valid IBAN"]`)
+		a.EqualError(v.Validate(&s{"DE89 3704 0044 0532 0130 0"}), `["'iban' must be a valid IBAN"]`)
+		a.EqualError(v.Validate(&s{"XX82 WEST 1234 5698 7654 32"}), `["'iban' must be a valid IBAN"]`)
+		a.EqualError(v.Validate(&s{"GB82 WEST 1234 5698 7654 3!"}), `["'iban' must be a valid IBAN"]`)
+		a.EqualError(v.Validate(&s{"gb82 west 1234 5698 7654 32"}), `["'iban' must be a valid IBAN"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the iban tag must be applied to a string"]`)
+	}) && t.Run("multipleof", func(t *testing.T) {
+		type s struct {
+			Quantity int `json:"quantity" validate:"multipleof:5"`
+		}
+		type s1 struct {
+			Quantity uint8 `json:"quantity" validate:"multipleof:5"`
+		}
+		type s2 struct {
+			Price float64 `json:"price" validate:"multipleof:0.1"`
+		}
+		type s3 struct {
+			Quantity int `json:"quantity" validate:"multipleof:0.5"`
+		}
+		var s4 struct {
+			Quantity string `json:"quantity" validate:"multipleof:5"`
+		}
+		var s5 struct {
+			Quantity int `json:"quantity" validate:"multipleof:0"`
 		}
-		s5 := s{}
 		var s6 struct {
-			Uint uint `json:"a" validate:"or:Int,String"`
+			Quantity int `json:"quantity" validate:"multipleof"`
 		}
 		v := New()
 		a := assert.New(t)
-		a.EqualError(v.Validate(&s1), `["'a', 'b' and 'c' must be set"]`)
-		a.EqualError(v.Validate(&s2), `["'a', 'b' and 'c' must be set"]`)
-		a.EqualError(v.Validate(&s3), `["'a', 'b' and 'c' must be set"]`)
-		a.Nil(v.Validate(&s4))
-		a.EqualError(v.Validate(&s5), `["'a', 'b' and 'c' must be set"]`)
-		a.EqualError(v.CheckSyntax(&s6), "'.Int' is not a valid field")
+		a.Nil(v.Validate(&s{0}))
+		a.Nil(v.Validate(&s{15}))
+		a.Nil(v.Validate(&s{-10}))
+		a.EqualError(v.Validate(&s{7}), `["'quantity' must be a multiple of 5"]`)
+		a.Nil(v.Validate(&s1{255}))
+		a.EqualError(v.Validate(&s1{254}), `["'quantity' must be a multiple of 5"]`)
+		a.Nil(v.Validate(&s2{0.3}))
+		a.Nil(v.Validate(&s2{12.7}))
+		a.EqualError(v.Validate(&s2{0.35}), `["'price' must be a multiple of 0.1"]`)
+		a.Nil(v.Validate(&s3{3}))
+		a.EqualError(v.CheckSyntax(&s4), `["the multipleof tag must be applied to a number"]`)
+		a.EqualError(v.CheckSyntax(&s5), `["multipleof requires one number other than 0"]`)
+		a.EqualError(v.CheckSyntax(&s6), `["multipleof requires one number other than 0"]`)
+	}) && t.Run("required_with", func(t *testing.T) {
+		type s struct {
+			ShippingStreet string
+			ShippingCity   string
+			ShippingZip    string `json:"shippingZip" validate:"required_with:ShippingStreet,ShippingCity"`
+		}
+		var s1 struct {
+			ShippingZip string `json:"shippingZip" validate:"required_with:Missing"`
+		}
+		var s2 struct {
+			ShippingZip string `json:"shippingZip" validate:"required_with"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{}))
+		a.Nil(v.Validate(&s{"1 Main St", "Springfield", "12345"}))
+		a.EqualError(v.Validate(&s{ShippingStreet: "1 Main St"}), `["'shippingZip' is required"]`)
+		a.EqualError(v.Validate(&s{ShippingCity: "Springfield"}), `["'shippingZip' is required"]`)
+		a.EqualError(New(&Config{SkipEmpty: true}).Validate(&s{ShippingCity: "Springfield"}), `["'shippingZip' is required"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'.Missing' is not a valid field"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["required_with requires at least one field name"]`)
+	}) && t.Run("required_without", func(t *testing.T) {
+		type s struct {
+			Email string `json:"email" validate:"required_without:Phone"`
+			Phone string `json:"phone" validate:"required_without:Email"`
+		}
+		var s1 struct {
+			Email string `json:"email" validate:"required_without:Missing"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{Email: "a@b.co"}))
+		a.Nil(v.Validate(&s{Phone: "5551234"}))
+		a.Nil(v.Validate(&s{"a@b.co", "5551234"}))
+		a.EqualError(v.Validate(&s{}), `["'email' is required","'phone' is required"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'.Missing' is not a valid field"]`)
+	}) && t.Run("before and after", func(t *testing.T) {
+		type s struct {
+			IssuedAt  time.Time `json:"issuedAt" validate:"before:now"`
+			ExpiresAt time.Time `json:"expiresAt" validate:"after:now & after:$IssuedAt"`
+		}
+		type s1 struct {
+			Launch string `json:"launch" validate:"after:2020-01-01 & before:'2030-01-01T00:00:00Z'"`
+		}
+		var s2 struct {
+			Launch int `json:"launch" validate:"after:now"`
+		}
+		var s3 struct {
+			Launch time.Time `json:"launch" validate:"after:tomorrow"`
+		}
+		var s4 struct {
+			Launch time.Time `json:"launch" validate:"before"`
+		}
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		v := New(&Config{Now: func() time.Time { return now }})
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{now.Add(-time.Hour), now.Add(time.Hour)}))
+		a.EqualError(v.Validate(&s{now.Add(time.Hour), now.Add(-time.Hour)}), `["'issuedAt' must be in the past","'expiresAt' must be in the future"]`)
+		a.EqualError(v.Validate(&s{now, now}), `["'issuedAt' must be in the past","'expiresAt' must be in the future"]`)
+		a.EqualError(v.Validate(&s{now.Add(-time.Hour), now.Add(-2 * time.Hour)}), `["'expiresAt' must be in the future"]`)
+		a.EqualError(v.Validate(&s{now.Add(-time.Hour), now.Add(-time.Minute)}, language.Spanish), `["'expiresAt' debe estar en el futuro"]`)
+		a.Nil(NewWith(WithNow(func() time.Time { return now.Add(-2 * time.Hour) })).Validate(&s{now.Add(-3 * time.Hour), now.Add(-time.Hour)}))
+
+		// strings are read as RFC3339 times and compared to literal dates and times
+		a.Nil(v.Validate(&s1{"2025-06-01T12:00:00Z"}))
+		a.EqualError(v.Validate(&s1{"2019-12-31T23:59:59Z"}), `["'launch' must be after 2020-01-01"]`)
+		a.EqualError(v.Validate(&s1{"2030-01-01T00:00:00Z"}), `["'launch' must be before 2030-01-01T00:00:00Z"]`)
+		a.EqualError(v.Validate(&s1{"not a time"}), `["'launch' must be after 2020-01-01"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["the after tag must be applied to a time or a string"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'tomorrow' is not a valid date or RFC3339 time for after"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["before requires exactly one parameter"]`)
+	}) && t.Run("bytesize", func(t *testing.T) {
+		type s struct {
+			Bio string `json:"bio" validate:"bytesize:8"`
+		}
+		type s1 struct {
+			Avatar []byte `json:"avatar" validate:"bytesize:2,4"`
+		}
+		var s2 struct {
+			Bio []rune `json:"bio" validate:"bytesize:8"`
+		}
+		var s3 struct {
+			Bio string `json:"bio" validate:"bytesize"`
+		}
+		var s4 struct {
+			Bio string `json:"bio" validate:"bytesize:-1"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{}))
+		a.Nil(v.Validate(&s{"12345678"}))
+		a.Nil(v.Validate(&s{"éééé"}))
+		a.EqualError(v.Validate(&s{"123456789"}), `["'bio' must be at most 8 bytes"]`)
+		a.EqualError(v.Validate(&s{"ééééé"}), `["'bio' must be at most 8 bytes"]`)
+		a.EqualError(v.Validate(&s{"😀😀😀"}), `["'bio' must be at most 8 bytes"]`)
+		a.Nil(v.Validate(&s1{[]byte("é")}))
+		a.EqualError(v.Validate(&s1{[]byte("x")}), `["'avatar' must be 2 to 4 bytes"]`)
+		a.EqualError(v.Validate(&s1{[]byte("ééé")}), `["'avatar' must be 2 to 4 bytes"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["the bytesize tag must be applied to a string or a []byte"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["bytesize requires a max or a min and a max"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["'-1' is not a valid size for bytesize"]`)
+	}) && t.Run("ltfield, ltefield, gtfield and gtefield", func(t *testing.T) {
+		type s struct {
+			Subtotal int     `json:"subtotal"`
+			Discount float64 `json:"discount" validate:"ltfield:Subtotal"`
+			Credit   int     `json:"credit" validate:"ltefield:Subtotal"`
+			Total    float64 `json:"total" validate:"gtfield:Discount"`
+			Paid     uint    `json:"paid" validate:"gtefield:Credit"`
+		}
+		var s1 struct {
+			Discount float64 `json:"discount" validate:"ltfield:Missing"`
+		}
+		var s2 struct {
+			Discount float64 `json:"discount" validate:"gtfield"`
+		}
+		var s3 struct {
+			Name     string  `json:"name"`
+			Discount float64 `json:"discount" validate:"gtefield:Name"`
+		}
+		var s4 struct {
+			Subtotal int    `json:"subtotal"`
+			Discount string `json:"discount" validate:"ltefield:Subtotal"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{Subtotal: 100, Discount: 99.5, Credit: 100, Total: 100, Paid: 100}))
+		a.EqualError(v.Validate(&s{Subtotal: 100, Discount: 100, Credit: 101, Total: 100, Paid: 100}), `["'discount' must be less than 'subtotal'","'credit' must be 'subtotal' or less","'total' must be greater than 'discount'","'paid' must be 'credit' or more"]`)
+		a.EqualError(v.Validate(&s{Subtotal: 10, Discount: 5, Credit: 10, Total: 5.5, Paid: 9}), `["'paid' must be 'credit' or more"]`)
+		a.EqualError(v.Validate(&s{Subtotal: 10, Discount: 5, Credit: 10, Total: 5, Paid: 10}), `["'total' must be greater than 'discount'"]`)
+
+		// unsigned values above math.MaxInt64 don't wrap around, and are compared to negative values by their sign
+		type s5 struct {
+			Min int64  `json:"min"`
+			Max uint64 `json:"max" validate:"gtfield:Min"`
+		}
+		a.Nil(v.Validate(&s5{0, math.MaxUint64}))
+		a.Nil(v.Validate(&s5{-1, 0}))
+		a.EqualError(v.Validate(&s5{math.MaxInt64, math.MaxInt64}), `["'max' must be greater than 'min'"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'.Missing' is not a valid field"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["gtfield requires exactly one field name"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'Name' can't be compared to 'discount'"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["the ltefield tag must be applied to a number or a time"]`)
+
+		// the field name can be a reference or quoted, but not a number
+		type s6 struct {
+			Min int `json:"min"`
+			Max int `json:"max" validate:"gtfield:$Min"`
+			Cap int `json:"cap" validate:"gtefield:'Max'"`
+		}
+		var s7 struct {
+			Max int `json:"max" validate:"gtfield:10"`
+		}
+		a.Nil(v.Validate(&s6{1, 2, 2}))
+		a.EqualError(v.Validate(&s6{2, 2, 1}), `["'max' must be greater than 'min'","'cap' must be 'max' or more"]`)
+		a.EqualError(v.CheckSyntax(&s7), `["'10' is not a valid field name for gtfield"]`)
+	}) && t.Run("mac", func(t *testing.T) {
+		type s struct {
+			Device string `json:"device" validate:"mac"`
+		}
+		var s1 struct {
+			Device []byte `json:"device" validate:"mac"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"00:1a:2b:3c:4d:5e"}))
+		a.Nil(v.Validate(&s{"00-1A-2B-3C-4D-5E"}))
+		a.Nil(v.Validate(&s{"001a.2b3c.4d5e"}))
+		a.Nil(v.Validate(&s{"00:00:5e:00:53:01:02:03"}))
+		a.EqualError(v.Validate(&s{""}), `["'device' must be a valid MAC address"]`)
+		a.EqualError(v.Validate(&s{"00:1a:2b:3c:4d"}), `["'device' must be a valid MAC address"]`)
+		a.EqualError(v.Validate(&s{"00:1a:2b:3c:4d:zz"}), `["'device' must be a valid MAC address"]`)
+		a.EqualError(v.Validate(&s{"00:1a-2b:3c:4d:5e"}), `["'device' must be a valid MAC address"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the mac tag must be applied to a string"]`)
+	}) && t.Run("hostname", func(t *testing.T) {
+		type s struct {
+			Host string `json:"host" validate:"hostname"`
+		}
+		type s1 struct {
+			Host string `json:"host" validate:"hostname:rfc1035"`
+		}
+		var s2 struct {
+			Host []byte `json:"host" validate:"hostname"`
+		}
+		var s3 struct {
+			Host string `json:"host" validate:"hostname:rfc952"`
+		}
+		label := strings.Repeat("a", 63)
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"example.com"}))
+		a.Nil(v.Validate(&s{"localhost"}))
+		a.Nil(v.Validate(&s{"3com.example-host.co.uk"}))
+		a.Nil(v.Validate(&s{label + ".com"}))
+		a.EqualError(v.Validate(&s{label + "a.com"}), `["'host' must be a valid hostname"]`)
+		a.EqualError(v.Validate(&s{strings.Repeat(label+".", 4) + "com"}), `["'host' must be a valid hostname"]`)
+		a.EqualError(v.Validate(&s{"exa_mple.com"}), `["'host' must be a valid hostname"]`)
+		a.EqualError(v.Validate(&s{"-example.com"}), `["'host' must be a valid hostname"]`)
+		a.EqualError(v.Validate(&s{"example-.com"}), `["'host' must be a valid hostname"]`)
+		a.EqualError(v.Validate(&s{"example..com"}), `["'host' must be a valid hostname"]`)
+		a.EqualError(v.Validate(&s{""}), `["'host' must be a valid hostname"]`)
+		a.Nil(v.Validate(&s1{"example.com"}))
+		a.EqualError(v.Validate(&s1{"3com.com"}), `["'host' must be a valid hostname"]`)
+		var quoted struct {
+			Host string `json:"host" validate:"hostname:'rfc1035'"`
+		}
+		quoted.Host = "3com.com"
+		a.EqualError(v.Validate(&quoted), `["'host' must be a valid hostname"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["the hostname tag must be applied to a string"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["'rfc952' is not a valid param for hostname"]`)
+	}) && t.Run("pattern", func(t *testing.T) {
+		RegisterPattern("sku", `^[A-Z]{3}-[0-9]{4}$`)
+		type s struct {
+			SKU string `json:"sku" validate:"pattern:sku"`
+		}
+		var s1 struct {
+			SKU string `json:"sku" validate:"pattern:unknown"`
+		}
+		var s2 struct {
+			SKU string `json:"sku" validate:"pattern"`
+		}
+		var s3 struct {
+			SKU int `json:"sku" validate:"pattern:sku"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"ABC-1234"}))
+		a.EqualError(v.Validate(&s{"abc-1234"}), `["'sku' is not in a valid format"]`)
+		a.EqualError(v.Validate(&s{"ABC-12345"}), `["'sku' is not in a valid format"]`)
+
+		// the pattern name can be quoted
+		type s4 struct {
+			SKU string `json:"sku" validate:"pattern:'sku'"`
+		}
+		a.Nil(v.Validate(&s4{"ABC-1234"}))
+		a.EqualError(v.Validate(&s4{"abc-1234"}), `["'sku' is not in a valid format"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["'unknown' is not a registered pattern"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["pattern requires exactly one pattern name"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the pattern tag must be applied to a string"]`)
+		a.Panics(func() { RegisterPattern("bad", `[`) })
+	}) && t.Run("nonempty", func(t *testing.T) {
+		type s struct {
+			Items []string       `json:"items" validate:"nonempty"`
+			Tags  map[string]int `json:"tags" validate:"nonempty"`
+		}
+		type s1 struct {
+			Items *[]string `json:"items" validate:"nonempty"`
+		}
+		var s2 struct {
+			Items string `json:"items" validate:"nonempty"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{[]string{"a"}, map[string]int{"a": 1}}))
+		a.EqualError(v.Validate(&s{}), `["'items' must not be empty","'tags' must not be empty"]`)
+		a.EqualError(v.Validate(&s{[]string{}, map[string]int{}}), `["'items' must not be empty","'tags' must not be empty"]`)
+		a.EqualError(v.Validate(&s1{}), `["'items' must not be empty"]`)
+		a.EqualError(v.Validate(&s1{&[]string{}}), `["'items' must not be empty"]`)
+		a.Nil(v.Validate(&s1{&[]string{"a"}}))
+		a.EqualError(New(&Config{SkipEmpty: true}).Validate(&s{}), `["'items' must not be empty","'tags' must not be empty"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["the nonempty tag must be applied to a slice, map or array"]`)
+	}) && t.Run("printable", func(t *testing.T) {
+		type s struct {
+			Name string `json:"name" validate:"printable"`
+		}
+		var s1 struct {
+			Name []byte `json:"name" validate:"printable"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{"Jane Doe"}))
+		a.Nil(v.Validate(&s{"José Ñúñez 😀"}))
+		a.EqualError(v.Validate(&s{"Jane\x00Doe"}), `["'name' must not contain control characters"]`)
+		a.EqualError(v.Validate(&s{"Jane\nDoe"}), `["'name' must not contain control characters"]`)
+		a.EqualError(v.Validate(&s{"\x1b[31mJane"}), `["'name' must not contain control characters"]`)
+		a.EqualError(v.Validate(&s{"Jane\u200bDoe"}), `["'name' must not contain control characters"]`)
+		a.EqualError(v.CheckSyntax(&s1), `["the printable tag must be applied to a string"]`)
+	}) && t.Run("interface rules", func(t *testing.T) {
+		RegisterInterfaceRule("stringer", reflect.TypeOf((*fmt.Stringer)(nil)).Elem())
+		type s struct {
+			Plugin interface{} `json:"plugin" validate:"stringer"`
+		}
+		type s1 struct {
+			Timeout time.Duration `json:"timeout" validate:"stringer"`
+		}
+		var s2 struct {
+			Count int `json:"count" validate:"stringer"`
+		}
+		v := New()
+		a := assert.New(t)
+		a.Nil(v.Validate(&s{time.Second}))
+		a.Nil(v.Validate(&s{language.Spanish}))
+		a.Nil(v.Validate(&s{}))
+		a.EqualError(v.Validate(&s{1}), `["'plugin' must implement Stringer"]`)
+		a.EqualError(v.Validate(&s{"plugin"}), `["'plugin' must implement Stringer"]`)
+		a.Nil(v.Validate(&s1{time.Second}))
+		a.Nil(v.CheckSyntax(&s1{}))
+		a.EqualError(v.CheckSyntax(&s2), `["the stringer tag must be applied to an interface or a type that implements fmt.Stringer"]`)
+
+		// pointer fields are dereferenced before the rule is applied, so pointer receivers are allowed
+		type s3 struct {
+			Plugin *pointerStringer `json:"plugin" validate:"stringer"`
+		}
+		a.NotPanics(func() {
+			a.Nil(v.Validate(&s3{&pointerStringer{}}))
+			a.Nil(v.Validate(&s3{}))
+		})
+		a.Nil(v.CheckSyntax(&s3{}))
+		a.Nil(v.Validate(&s{&pointerStringer{}}))
+		a.EqualError(v.Validate(&s{pointerStringer{}}), `["'plugin' must implement Stringer"]`)
+		a.Panics(func() { RegisterInterfaceRule("duration", reflect.TypeOf(time.Second)) })
+		a.Panics(func() { RegisterInterfaceRule("nil", nil) })
+	}) && t.Run("slug", func(t *testing.T) {
+		type s struct {
+			Slug string `json:"slug" validate:"slug"`
+		}
+		type s1 struct {
+			Slug string `json:"slug" validate:"slug:underscore"`
+		}
+		var s2 struct {
+			Slug string `json:"slug" validate:"slug:upper"`
+		}
+		var s3 struct {
+			Slug int `json:"slug" validate:"slug"`
+		}
+		v := New()
+		a := assert.New(t)
+		for _, slug := range []string{"post", "my-first-post", "2024-recap", "a1-b2-c3"} {
+			a.Nil(v.Validate(&s{slug}), slug)
+			a.Nil(v.Validate(&s1{slug}), slug)
+		}
+		for _, slug := range []string{"My-Post", "-post", "post-", "my--post", "my_post", "my post", "póst"} {
+			a.EqualError(v.Validate(&s{slug}), `["'slug' must be a valid url slug"]`, slug)
+		}
+		a.Nil(v.Validate(&s1{"my_first-post"}))
+		var quoted struct {
+			Slug string `json:"slug" validate:"slug:\"underscore\""`
+		}
+		quoted.Slug = "my_first-post"
+		a.Nil(v.Validate(&quoted))
+		a.EqualError(v.Validate(&s1{"_post"}), `["'slug' must be a valid url slug"]`)
+		a.EqualError(v.Validate(&s1{"my__post"}), `["'slug' must be a valid url slug"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'upper' is not a valid param for slug"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["the slug tag must be applied to a string"]`)
+	}) && t.Run("decimal", func(t *testing.T) {
+		type s struct {
+			Amount string `json:"amount" validate:"decimal:10,2"`
+		}
+		type s1 struct {
+			Count string `json:"count" validate:"decimal:3"`
+		}
+		var s2 struct {
+			Amount string `json:"amount" validate:"decimal:2,3"`
+		}
+		var s3 struct {
+			Amount string `json:"amount" validate:"decimal"`
+		}
+		var s4 struct {
+			Amount float64 `json:"amount" validate:"decimal:10,2"`
+		}
+		v := New()
+		a := assert.New(t)
+		for _, amount := range []string{"0", "19.99", "-19.9", "+0.01", "12345678.00", "00012345678.50"} {
+			a.Nil(v.Validate(&s{amount}), amount)
+		}
+		a.EqualError(v.Validate(&s{"19.999"}), `["'amount' must have at most 2 decimal places"]`)
+		a.EqualError(v.Validate(&s{"123456789.00"}), `["'amount' must have at most 8 digits before the decimal point"]`)
+		for _, amount := range []string{"", "abc", "$19.99", "19.", ".99", "1,000.00", "1e3"} {
+			a.EqualError(v.Validate(&s{amount}), `["'amount' must be a decimal number"]`, amount)
+		}
+		a.Nil(v.Validate(&s1{"999"}))
+		a.EqualError(v.Validate(&s1{"1.5"}), `["'count' must have at most 0 decimal places"]`)
+		a.EqualError(v.Validate(&s1{"1000"}), `["'count' must have at most 3 digits before the decimal point"]`)
+		a.EqualError(v.Validate(&s{"19.999"}, language.Spanish), `["'amount' debe tener como máximo 2 decimales"]`)
+		a.EqualError(v.CheckSyntax(&s2), `["'3' is not a valid scale for decimal"]`)
+		a.EqualError(v.CheckSyntax(&s3), `["decimal requires a precision or a precision and a scale"]`)
+		a.EqualError(v.CheckSyntax(&s4), `["the decimal tag must be applied to a string"]`)
 	}); !pass {
 		t.Fatal("error")
 	}
 }
+
+// enumLevel is a Go enum used to test the enum rule
+type enumLevel int
+
+const (
+	enumLevelLow enumLevel = iota
+	enumLevelHigh
+)
+
+func (l enumLevel) String() string {
+	switch l {
+	case enumLevelLow:
+		return "low"
+	case enumLevelHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+// sourceOrder is used to test validating the values returned by methods
+type sourceOrder struct {
+	Items    []float64 `json:"items"`
+	Discount float64   `json:"discount"`
+	Net      float64   `json:"netTotal" validate:"number:0" source:"NetTotal"`
+}
+
+// NetTotal returns the total of the items after the discount
+func (o *sourceOrder) NetTotal() float64 {
+	return o.Sum(0) - o.Discount
+}
+
+// Sum returns the total of the items plus the amount passed in
+func (o sourceOrder) Sum(plus float64) float64 {
+	for _, item := range o.Items {
+		plus += item
+	}
+	return plus
+}
+
+// sourceSum has a source method with the wrong signature
+type sourceSum struct {
+	Total float64 `json:"total" validate:"number:0" source:"Sum"`
+}
+
+// Sum returns the total plus the amount passed in
+func (s sourceSum) Sum(plus float64) float64 {
+	return s.Total + plus
+}
+
+// errReader is a reader that always fails
+type errReader struct {
+	err error
+}
+
+// Read returns the error of the reader
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
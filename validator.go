@@ -4,7 +4,7 @@
 //
 // Rule Syntax
 //
-// Rules can be joined together with "and"s (&) and "or"s (|)
+// Rules can be joined together with "and"s (& or &&) and "or"s (| or ||)
 //
 //  type Struct struct {
 //    Field   string `json:"field" validate:"omitempty | email"`   // 'field' must be a valid email address or not set at all
@@ -18,6 +18,18 @@
 //    Field  string `json:"field" validate:"eq:one,two,three"` // 'field' must equal either "one", "two", or "three"
 //  }
 //
+// Params can be quoted with either single or double quotes. Quotes and backslashes inside of a quoted param are escaped with a backslash.
+//
+//  type Struct struct {
+//    Field  string `json:"field" validate:"glob:'it\\'s *'"` // 'field' must start with "it's "
+//  }
+//
+// A rule followed by a ? is optional, it only applies to fields that are set. `email?` is the same as `empty | email`.
+//
+//  type Struct struct {
+//    Field  string `json:"field" validate:"email?"` // 'field' must be a valid email address or not set at all
+//  }
+//
 // Finally, its worth noting the validators can cross reference other fields.
 //
 //  type Struct struct {
@@ -25,13 +37,37 @@
 //    Field2 string `json:"field2"`
 //  }
 //
+// The values of other fields can be passed to rules that compare values, like "eq", "number" or "lt", by prefixing the
+// name of the field with a $.
+//
+//  type Struct struct {
+//    Count  int `json:"count" validate:"lt:$Max"` // 'count' must be less than 'max'
+//    Max    int `json:"max"`
+//  }
+//
+// Fields of nested structs are referenced with dots, and fields of the value passed to Validate with the "root." prefix,
+// which lets the elements of a slice depend on the struct that contains them.
+//
+//  type Item struct {
+//    Currency string `json:"currency" validate:"eqfield:root.Currency"` // 'currency' must match the invoice's 'currency'
+//  }
+//
+// Computed values can be validated too, by naming a method with no arguments in the "source" tag of a field.
+//
+//  type Struct struct {
+//    Total  float64 `json:"total" validate:"number:0" source:"NetTotal"` // 'total' must be 0 or more, where total is Struct.NetTotal()
+//  }
+//
 //
 package validator
 
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/text/language"
 )
@@ -42,6 +78,16 @@ var debug bool
 // DefaultTag is the tage used if Config.Tag is not set
 const DefaultTag = "validate"
 
+// DefaultSyntaxCheckTimeout is how long CheckSyntax waits for the rules to finish if Config.SyntaxCheckTimeout is not set
+const DefaultSyntaxCheckTimeout = 5 * time.Second
+
+// DefaultNameTag is the tag used to name fields in error messages if Config.NameTag is not set
+const DefaultNameTag = "json"
+
+// SourceTag is the tag that names a method whose return value is validated in place of the value of the field, e.g.
+// `validate:"number:0" source:"NetTotal"`. The method must take no arguments and return a single value.
+const SourceTag = "source"
+
 // DefaultValidator is the default validator used by the `Validate` and `CheckSyntax` funcs
 var DefaultValidator = New()
 
@@ -56,20 +102,176 @@ func CheckSyntax(i interface{}) error {
 	return DefaultValidator.CheckSyntax(i)
 }
 
+// Compile parses the validation tags of the types of the values passed in based on the 'DefaultRules', and returns any that
+// can't be parsed, e.g. because they use a rule that doesn't exist
+func Compile(is ...interface{}) error {
+	return DefaultValidator.Compile(is...)
+}
+
+// Valid returns true if the struct or slice passed in is valid based on the 'DefaultRules'
+func Valid(i interface{}, tags ...language.Tag) bool {
+	return DefaultValidator.Valid(i, tags...)
+}
+
+// MustValidate validates a struct or a slice based on the 'DefaultRules' and panics if it is not valid
+func MustValidate(i interface{}, tags ...language.Tag) {
+	DefaultValidator.MustValidate(i, tags...)
+}
+
+// ValidateGrouped validates a struct or a slice based on the 'DefaultRules' and returns the first error of each field by its path
+func ValidateGrouped(i interface{}, tags ...language.Tag) (map[string]error, error) {
+	return DefaultValidator.ValidateGrouped(i, tags...)
+}
+
+// ValidateVerbose validates a struct or a slice based on the 'DefaultRules' and returns the paths of every field whose validation tag was evaluated
+func ValidateVerbose(i interface{}, tags ...language.Tag) ([]string, error) {
+	return DefaultValidator.ValidateVerbose(i, tags...)
+}
+
+// ValidateFields validates only the fields of a struct whose names are passed in, based on the 'DefaultRules'
+func ValidateFields(i interface{}, fields []string, tags ...language.Tag) error {
+	return DefaultValidator.ValidateFields(i, fields, tags...)
+}
+
+// ValidateStream validates a struct or a slice based on the 'DefaultRules', and calls fn with each error as it is found
+func ValidateStream(i interface{}, fn func(path string, err error) bool, tags ...language.Tag) {
+	DefaultValidator.ValidateStream(i, fn, tags...)
+}
+
+// ValidateMap validates the values of a map based on the rule expressions of the keys in the rules map and the 'DefaultRules'
+func ValidateMap(m map[string]interface{}, rules map[string]string, tags ...language.Tag) error {
+	return DefaultValidator.ValidateMap(m, rules, tags...)
+}
+
+// RuleNames returns the sorted names of the rules the `DefaultValidator` can apply
+func RuleNames() []string {
+	return DefaultValidator.RuleNames()
+}
+
 // Validator validates structs and slices
 type Validator interface {
 	// CheckSyntax cycles though all of the validation tags and returns bad syntax errors instead of panicing
 	CheckSyntax(interface{}) error
 
+	// Compile parses and caches the validation tags of the types of the values passed in, and of every struct type nested
+	// inside of them, without validating any values. It returns the tags that can't be parsed, e.g. because they use a rule
+	// that doesn't exist, so that misconfigured tags can be caught at startup instead of when a value is first validated.
+	// The values can be nil pointers, e.g. `Compile((*User)(nil))`.
+	Compile(...interface{}) error
+
 	// Validate validates a struct or a slice based on the information passed to the 'validate' tag.
-	// The error returned will be in English by default, but they can be changed to Spanish by setting the optional language.Tag.
+	// The error returned will be in the `Config.DefaultLanguage`, English by default, but they can be changed to Spanish by setting the optional language.Tag.
 	Validate(interface{}, ...language.Tag) error
+
+	// Valid returns true if Validate does not return an error
+	Valid(interface{}, ...language.Tag) bool
+
+	// MustValidate panics with the error returned by Validate, if there is one. Like `regexp.MustCompile`, it is intended
+	// for tests and program initialization, where invalid data is a programmer error.
+	MustValidate(interface{}, ...language.Tag)
+
+	// ValidateGrouped validates the same way as Validate, and also returns the first error of each field that failed keyed by
+	// its path, e.g. `address.city`, which is what most forms show next to their fields. The map is nil if there aren't any errors.
+	ValidateGrouped(interface{}, ...language.Tag) (map[string]error, error)
+
+	// ValidateVerbose validates the same way as Validate, and also returns the paths of every field whose validation tag was evaluated,
+	// whether it passed or failed. It's useful for making sure the validation tags are being picked up.
+	ValidateVerbose(interface{}, ...language.Tag) ([]string, error)
+
+	// ValidateFields validates the same way as Validate, but only validates the fields whose names are passed in, e.g. the fields
+	// sent to a PATCH endpoint. Fields are named by their json or go names, and nested fields are separated by dots, e.g. `address.city`.
+	// Naming a field also validates all of the fields nested inside of it.
+	ValidateFields(interface{}, []string, ...language.Tag) error
+
+	// ValidateStream validates the same way as Validate, but calls the func passed in with the path and the *FieldError of each
+	// error as it is found instead of returning them, e.g. to write the errors of a very large slice to a stream without holding
+	// all of them in memory. Validation stops if the func returns false.
+	ValidateStream(interface{}, func(path string, err error) bool, ...language.Tag)
+
+	// ValidateMap validates the values of a map, e.g. a decoded json request body, against the rule expressions of the
+	// same keys in the rules map, e.g. map[string]string{"email": "required & email"}. Keys that are missing or nil are
+	// only validated by expressions that check whether or not they're set, like `required` or `or`. Values that a rule can't
+	// be applied to, like a number for `email`, are returned as errors of their keys instead of panicing.
+	ValidateMap(map[string]interface{}, map[string]string, ...language.Tag) error
+
+	// RuleNames returns the sorted names of the rules the validator can apply
+	RuleNames() []string
+
+	// ClearCache empties the cache of results kept when `Config.CacheResults` is set
+	ClearCache()
 }
 
 // Config configures the validator
 type Config struct {
 	Tag   string
 	Rules Rules
+
+	// ExtendDefaults merges the Rules on top of a copy of the `DefaultRules` instead of replacing them.
+	// Rules win over the default rules with the same name.
+	ExtendDefaults bool
+
+	// NameTag is the tag that the names of fields in error messages are read from, e.g. "json", "yaml" or "form".
+	// Fields without the tag are referred to by their go name.
+	NameTag string
+
+	// DefaultLanguage is the language that error messages are translated into when a language isn't passed in to `Validate`.
+	// It defaults to English.
+	DefaultLanguage language.Tag
+
+	// SkipEmpty skips the rules of fields that are not set, unless they use a rule that checks whether or not fields are set,
+	// like `required` or `xor`. This saves having to write `empty | ...` for every optional field.
+	SkipEmpty bool
+
+	// DerefPointers treats the other fields read by rules like `xor`, `or`, `and`, `required_with` and `required_without` as unset
+	// when they are pointers to zero values. By default, any of those fields that isn't nil is set. It doesn't change the rules of
+	// a field that is a pointer, since the field's own pointer is always dereferenced before its rules are applied, e.g. `required`
+	// on a *int that points to 0 fails either way.
+	DerefPointers bool
+
+	// TypeNames adds the go type of a field after its name in error messages, e.g. `'count' (int) must be 1 or more`.
+	// It is intended for debugging and developer facing validation.
+	TypeNames bool
+
+	// ReportAll evaluates both sides of every `&` so that every failure of a field is reported instead of just the first one.
+	// `|`s still pass if either side passes, but report the failures of both sides when neither does.
+	ReportAll bool
+
+	// CacheResults caches the result of validating a value, so that validating an identical value again returns the cached result
+	// without running any rules. Only values made up entirely of booleans, numbers, strings, arrays and structs are cached, and
+	// the rules they use must always return the same result for the same value. The cache is emptied by `ClearCache`.
+	CacheResults bool
+
+	// SyntaxCheckTimeout is how long CheckSyntax waits for the rules to finish before returning an error.
+	// It defaults to `DefaultSyntaxCheckTimeout`.
+	SyntaxCheckTimeout time.Duration
+
+	// OnRuleExecuted is called after every rule that is run, with the name of the rule, the path of the field,
+	// how long the rule took and the error it returned. It is intended for finding slow rules, and must be safe to
+	// call concurrently if the validator is shared.
+	OnRuleExecuted func(name string, field string, dur time.Duration, err error)
+
+	// SortErrors sorts the errors returned by their path and then by their message with `FieldErrors.Sort`, so that
+	// errors from the values of maps are always returned in the same order
+	SortErrors bool
+
+	// ErrorFormatter renders the message of each *FieldError returned, e.g. to add the path of the field or an error code.
+	// It is used by `FieldError.Error` and `FieldError.MarshalJSON`, so it must read the message from `FieldError.Message`.
+	// Messages are rendered as they are if it is nil.
+	ErrorFormatter func(fe *FieldError) string
+
+	// SkipUnexportedTags skips the validation tags of unexported fields instead of reporting them as errors. Unexported fields
+	// can't be read, so they are never validated, and by default giving one a validation tag is reported by every call to
+	// `Validate`, `CheckSyntax` and `Compile` as a mistake.
+	SkipUnexportedTags bool
+
+	// MaxDepth is how many levels of nested structs, slices, arrays and maps are validated. Values that are nested deeper
+	// are reported as errors instead of being validated. There is no limit if it is 0. Values that contain themselves, like
+	// a node of a tree that points back to its parent, are only validated once either way.
+	MaxDepth int
+
+	// Now returns the current time that rules like `after:now` compare fields to. It defaults to `time.Now`, and is intended
+	// for fixing the time in tests.
+	Now func() time.Time
 }
 
 // New returns a new Validator
@@ -88,57 +290,709 @@ type Config struct {
 func New(cfg ...*Config) Validator {
 	var v validator
 	v.tag = DefaultTag
+	v.nameTag = DefaultNameTag
 	v.rules = DefaultRules
 	v.parser = newParser()
 	v.parser.debug = debug
+	v.types = make(map[reflect.Type][]field)
+	v.syntaxCheckTimeout = DefaultSyntaxCheckTimeout
+	v.now = time.Now
+	v.language = language.English
 	if cfg == nil || len(cfg) == 0 {
 		return &v
 	}
 	if len(cfg[0].Tag) > 0 {
 		v.tag = cfg[0].Tag
 	}
-	if cfg[0].Rules != nil && len(cfg[0].Rules) > 0 {
+	if len(cfg[0].NameTag) > 0 {
+		v.nameTag = cfg[0].NameTag
+	}
+	if cfg[0].SyntaxCheckTimeout > 0 {
+		v.syntaxCheckTimeout = cfg[0].SyntaxCheckTimeout
+	}
+	if cfg[0].Rules != nil && len(cfg[0].Rules) > 0 && cfg[0].ExtendDefaults {
+		v.rules = mergeRules(DefaultRules, cfg[0].Rules)
+	} else if cfg[0].Rules != nil && len(cfg[0].Rules) > 0 {
 		v.rules = cfg[0].Rules
 	}
+	v.derefPointers = cfg[0].DerefPointers
+	v.skipEmpty = cfg[0].SkipEmpty
+	v.typeNames = cfg[0].TypeNames
+	v.reportAll = cfg[0].ReportAll
+	v.onRuleExecuted = cfg[0].OnRuleExecuted
+	v.maxDepth = cfg[0].MaxDepth
+	v.skipUnexportedTags = cfg[0].SkipUnexportedTags
+	v.sortErrors = cfg[0].SortErrors
+	v.errorFormatter = cfg[0].ErrorFormatter
+	if cfg[0].Now != nil {
+		v.now = cfg[0].Now
+	}
+	if cfg[0].DefaultLanguage != language.Und {
+		v.language = cfg[0].DefaultLanguage
+	}
+	if cfg[0].CacheResults {
+		v.results = make(map[result]error)
+	}
 	return &v
 }
 
+// Option configures a validator created with `NewWith`
+type Option func(*Config)
+
+// WithTag sets the tag that the rules are read from, e.g. `WithTag("v")` reads `v:"required"`
+func WithTag(tag string) Option {
+	return func(cfg *Config) {
+		cfg.Tag = tag
+	}
+}
+
+// WithNameTag sets the tag that the names of fields in error messages are read from, e.g. "yaml"
+func WithNameTag(nameTag string) Option {
+	return func(cfg *Config) {
+		cfg.NameTag = nameTag
+	}
+}
+
+// WithRules replaces the `DefaultRules` with the rules passed in. The rules are merged with the rules of any earlier
+// `WithRules` or `WithExtendedRules` options, so they can be split across options, and the last option decides whether
+// the `DefaultRules` are replaced or extended
+func WithRules(rules Rules) Option {
+	return func(cfg *Config) {
+		cfg.Rules = mergeRules(cfg.Rules, rules)
+		cfg.ExtendDefaults = false
+	}
+}
+
+// WithExtendedRules adds the rules passed in to a copy of the `DefaultRules`. Like `WithRules`, the rules are merged with
+// the rules of any earlier options
+func WithExtendedRules(rules Rules) Option {
+	return func(cfg *Config) {
+		cfg.Rules = mergeRules(cfg.Rules, rules)
+		cfg.ExtendDefaults = true
+	}
+}
+
+// WithDefaultLanguage sets the language that error messages are translated into when a language isn't passed in, e.g. `language.Spanish`
+func WithDefaultLanguage(tag language.Tag) Option {
+	return func(cfg *Config) {
+		cfg.DefaultLanguage = tag
+	}
+}
+
+// WithNow sets the func that returns the current time for rules like `after:now`, e.g. to fix the time in tests
+func WithNow(now func() time.Time) Option {
+	return func(cfg *Config) {
+		cfg.Now = now
+	}
+}
+
+// mergeRules returns a copy of a with the rules in b added to it
+func mergeRules(a, b Rules) Rules {
+	rules := make(Rules, len(a)+len(b))
+	for name, rule := range a {
+		rules[name] = rule
+	}
+	for name, rule := range b {
+		rules[name] = rule
+	}
+	return rules
+}
+
+// NewWith returns a new Validator configured by the options passed in. It is the same as passing a `Config` to `New`.
+//
+// Example
+//
+//   v := NewWith(WithTag("v"), WithExtendedRules(Rules{"company": Company}))
+//
+func NewWith(opts ...Option) Validator {
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return New(&cfg)
+}
+
 type validator struct {
-	tag    string
-	rules  Rules
-	parser *parser
+	tag           string
+	nameTag       string
+	rules         Rules
+	parser        *parser
+	derefPointers bool
+	skipEmpty     bool
+	typeNames     bool
+	reportAll     bool
+	sortErrors    bool
+
+	// syntaxCheckTimeout is how long CheckSyntax waits for the rules to finish
+	syntaxCheckTimeout time.Duration
+
+	// onRuleExecuted is called after every rule that is run, if it is set
+	onRuleExecuted func(name string, field string, dur time.Duration, err error)
+
+	// now returns the current time
+	now func() time.Time
+
+	// language is the language of the error messages when one isn't passed in
+	language language.Tag
+
+	// maxDepth is how many levels of nested values are validated, or 0 if there is no limit
+	maxDepth int
+
+	// skipUnexportedTags skips the validation tags of unexported fields instead of reporting them
+	skipUnexportedTags bool
+
+	// errorFormatter renders the messages of the errors returned, if it is set
+	errorFormatter func(fe *FieldError) string
+
+	// types caches the fields of every struct type the validator has seen
+	types map[reflect.Type][]field
+	mutex sync.RWMutex
+
+	// results caches the results of validating values when `Config.CacheResults` is set
+	results map[result]error
+}
+
+// result is the key of a cached result
+type result struct {
+	value interface{}
+	tag   string
+}
+
+// field is the information about a struct field that doesn't change between calls to Validate
+type field struct {
+	// name is the json name of the field
+	name string
+
+	// goName is the name of the field in the go struct
+	goName string
+
+	// isIgnored is true if the field's validation tag is "-"
+	isIgnored bool
+
+	// isNoDive is true if the fields nested inside of the field should not be validated
+	isNoDive bool
+
+	// isPresenceChecked is true if the field uses one of the `presenceRules`
+	isPresenceChecked bool
+
+	// source is the name of the method whose return value is validated in place of the field's value
+	source string
+
+	// parsed is the parse tree of the field's validation tag. It is nil if the field doesn't have one
+	parsed *node
+
+	// err is the error returned while parsing the field's validation tag
+	err error
+}
+
+// fieldName returns the name of a struct field to use in error messages
+func (v *validator) fieldName(sf reflect.StructField) string {
+	nameTag := DefaultNameTag
+	if v != nil {
+		nameTag = v.nameTag
+	}
+	if name, ok := sf.Tag.Lookup(nameTag); ok {
+		if name = strings.Split(name, ",")[0]; len(name) > 0 {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+// parse returns the parse tree of a rule expression
+func (v *validator) parse(expression string) (*node, error) {
+	v.mutex.RLock()
+	parsed, ok := v.parser.cache[expression]
+	v.mutex.RUnlock()
+	if ok {
+		return parsed, nil
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return v.parser.parse(expression, v.rules)
+}
+
+// fields returns the fields of a struct type, parsing them the first time the type is seen
+func (v *validator) fields(iType reflect.Type) []field {
+	v.mutex.RLock()
+	fields, ok := v.types[iType]
+	v.mutex.RUnlock()
+	if ok {
+		return fields
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if fields, ok := v.types[iType]; ok {
+		return fields
+	}
+	fields = make([]field, iType.NumField())
+	for i := range fields {
+		f, sf := &fields[i], iType.Field(i)
+
+		// name the field in the error messages
+		f.name = v.fieldName(sf)
+		f.goName = sf.Name
+
+		// skip unexported fields, since their values can't be read, and report the ones with validation tags unless
+		// `Config.SkipUnexportedTags` is set. The exported fields of embedded structs can still be read, so they're validated as usual
+		validator, ok := sf.Tag.Lookup(v.tag)
+		if isUnexported := len(sf.PkgPath) > 0; isUnexported && ok && validator != "-" && !v.skipUnexportedTags {
+			f.err = fmt.Errorf("'%s' is unexported and can't be validated", sf.Name)
+			f.isNoDive = !sf.Anonymous
+			continue
+		} else if isUnexported {
+			f.isIgnored = !sf.Anonymous || (ok && validator == "-")
+			continue
+		}
+
+		// parse the validation tag
+		if !ok {
+			continue
+		} else if validator == "-" {
+			f.isIgnored = true
+		} else if f.parsed, f.err = v.parser.parse(validator, v.rules); f.err == nil {
+			f.isNoDive = f.parsed.hasRule("nodive")
+			for _, rule := range presenceRules {
+				f.isPresenceChecked = f.isPresenceChecked || f.parsed.hasRule(rule)
+			}
+		}
+
+		// make sure the source method can be called
+		if f.source = sf.Tag.Get(SourceTag); len(f.source) > 0 && f.err == nil {
+			m, ok := reflect.PtrTo(iType).MethodByName(f.source)
+			if !ok {
+				f.err = fmt.Errorf("'%s.%s' is not a valid method", iType.Name(), f.source)
+			} else if m.Type.NumIn() != 1 || m.Type.NumOut() != 1 {
+				f.err = fmt.Errorf("'%s.%s' must take no arguments and return a single value", iType.Name(), f.source)
+			}
+		}
+	}
+	v.types[iType] = fields
+	return fields
+}
+
+// Compile returns an implementation of Compile
+func (v *validator) Compile(is ...interface{}) error {
+	var errs FieldErrors
+	compiled := make(map[reflect.Type]bool)
+	for _, i := range is {
+		if i != nil {
+			v.compile(reflect.TypeOf(i), "", compiled, &errs)
+		}
+	}
+	if len(errs) > 0 {
+		v.finish(errs)
+		return errs
+	}
+	return nil
+}
+
+// compile parses the validation tags of a struct type and the struct types nested inside of it that haven't been compiled yet,
+// and adds the errors to errs
+func (v *validator) compile(iType reflect.Type, path string, compiled map[reflect.Type]bool, errs *FieldErrors) {
+	for kind := iType.Kind(); kind == reflect.Ptr || kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map; kind = iType.Kind() {
+		iType = iType.Elem()
+	}
+	if iType.Kind() != reflect.Struct || compiled[iType] {
+		return
+	}
+	compiled[iType] = true
+	for i, f := range v.fields(iType) {
+		fPath := f.name
+		if len(path) > 0 {
+			fPath = path + "." + f.name
+		}
+		if f.err != nil {
+			errs.Add(NewFieldError(fPath, f.err))
+		}
+		if !f.isIgnored && !f.isNoDive {
+			v.compile(iType.Field(i).Type, fPath, compiled, errs)
+		}
+	}
+}
+
+// languageOf returns the language passed in to one of the validation methods, or the default language if there isn't one
+func (v *validator) languageOf(tags []language.Tag) language.Tag {
+	if len(tags) > 0 {
+		return tags[0]
+	}
+	return v.language
 }
 
 // Validate returns an implementation of Validate
 func (v *validator) Validate(i interface{}, tags ...language.Tag) error {
 	iValue := reflect.ValueOf(i)
-	tag := language.English
-	if len(tags) > 0 {
-		tag = tags[0]
+	tag := v.languageOf(tags)
+
+	// look up the cached result
+	key, isCached := v.resultKey(iValue, tag)
+	if isCached {
+		v.mutex.RLock()
+		err, ok := v.results[key]
+		v.mutex.RUnlock()
+		if ok {
+			return err
+		}
+	}
+
+	var err error
+	if errs := v.traverse(&traversal{tag: tag, root: iValue}, iValue, ""); len(errs) > 0 {
+		v.finish(errs)
+		err = errs
+	}
+	if isCached {
+		v.mutex.Lock()
+		v.results[key] = err
+		v.mutex.Unlock()
 	}
-	if errs := v.traverse(tag, false, iValue, iValue); len(errs) > 0 {
+	return err
+}
+
+// resultKey returns the key of the cached result of a value, and false if the result of the value can't be cached
+func (v *validator) resultKey(iValue reflect.Value, tag language.Tag) (result, bool) {
+	if v.results == nil {
+		return result{}, false
+	}
+	for iValue.Kind() == reflect.Ptr || iValue.Kind() == reflect.Interface {
+		if iValue.IsNil() {
+			return result{}, false
+		}
+		iValue = iValue.Elem()
+	}
+	if !iValue.IsValid() || !isCacheable(iValue.Type()) {
+		return result{}, false
+	}
+	return result{iValue.Interface(), tag.String()}, true
+}
+
+// isCacheable returns true if a type is made up entirely of booleans, numbers, strings, arrays and structs, so that
+// two values of it are only equal if they contain the same data
+func isCacheable(iType reflect.Type) bool {
+	switch iType.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return isCacheable(iType.Elem())
+	case reflect.Struct:
+		for i := 0; i < iType.NumField(); i++ {
+			if !isCacheable(iType.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// ClearCache returns an implementation of ClearCache
+func (v *validator) ClearCache() {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if v.results != nil {
+		v.results = make(map[result]error)
+	}
+}
+
+// Valid returns an implementation of Valid
+func (v *validator) Valid(i interface{}, tags ...language.Tag) bool {
+	return v.Validate(i, tags...) == nil
+}
+
+// MustValidate returns an implementation of MustValidate
+func (v *validator) MustValidate(i interface{}, tags ...language.Tag) {
+	if err := v.Validate(i, tags...); err != nil {
+		panic(err)
+	}
+}
+
+// ValidateGrouped returns an implementation of ValidateGrouped
+func (v *validator) ValidateGrouped(i interface{}, tags ...language.Tag) (map[string]error, error) {
+	err := v.Validate(i, tags...)
+	if errs, ok := err.(FieldErrors); ok {
+		return errs.FirstByPath(), err
+	}
+	return nil, err
+}
+
+// source returns the value returned by the method of a struct
+func source(iValue reflect.Value, method string) reflect.Value {
+	if !iValue.CanAddr() {
+		ptr := reflect.New(iValue.Type())
+		ptr.Elem().Set(iValue)
+		iValue = ptr.Elem()
+	}
+	return iValue.Addr().MethodByName(method).Call(nil)[0]
+}
+
+// RuleNames returns an implementation of RuleNames
+func (v *validator) RuleNames() []string {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	names := make([]string, 0, len(v.rules))
+	for name := range v.rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateVerbose returns an implementation of ValidateVerbose
+func (v *validator) ValidateVerbose(i interface{}, tags ...language.Tag) ([]string, error) {
+	iValue := reflect.ValueOf(i)
+	t := traversal{root: iValue, tag: v.languageOf(tags), isVerbose: true}
+	if errs := v.traverse(&t, iValue, ""); len(errs) > 0 {
+		v.finish(errs)
+		return t.checked, errs
+	}
+	return t.checked, nil
+}
+
+// ValidateFields returns an implementation of ValidateFields
+func (v *validator) ValidateFields(i interface{}, fields []string, tags ...language.Tag) error {
+	iValue := reflect.ValueOf(i)
+	t := traversal{root: iValue, tag: v.languageOf(tags), mask: make([][]string, len(fields))}
+	for i, f := range fields {
+		t.mask[i] = strings.Split(f, ".")
+	}
+	if errs := v.traverse(&t, iValue, ""); len(errs) > 0 {
+		v.finish(errs)
 		return errs
 	}
 	return nil
 }
 
+// ValidateStream returns an implementation of ValidateStream
+func (v *validator) ValidateStream(i interface{}, fn func(path string, err error) bool, tags ...language.Tag) {
+	iValue := reflect.ValueOf(i)
+	t := traversal{root: iValue, tag: v.languageOf(tags)}
+	t.emit = func(path string, err error) bool {
+		v.finish(FieldErrors{err})
+		return fn(path, err)
+	}
+	v.traverse(&t, iValue, "")
+}
+
+// ValidateMap returns an implementation of ValidateMap
+func (v *validator) ValidateMap(m map[string]interface{}, rules map[string]string, tags ...language.Tag) error {
+	tag := v.languageOf(tags)
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs FieldErrors
+	mValue := reflect.ValueOf(m)
+	for _, key := range keys {
+		parsed, err := v.parse(rules[key])
+		if err != nil {
+			errs.Add(NewFieldError(key, err))
+			continue
+		}
+
+		// skip keys that aren't set, unless the expression checks whether or not they are
+		fValue := reflect.ValueOf(m[key])
+		if !fValue.IsValid() {
+			isPresenceChecked := false
+			for _, rule := range presenceRules {
+				isPresenceChecked = isPresenceChecked || parsed.hasRule(rule)
+			}
+			if !isPresenceChecked {
+				continue
+			}
+			fValue = mValue.MapIndex(reflect.ValueOf(key))
+			if !fValue.IsValid() {
+				fValue = reflect.Zero(mValue.Type().Elem())
+			}
+		}
+
+		var ps RuleParams
+		ps.Root = mValue
+		ps.RootType = mValue.Type()
+		ps.Parent = mValue
+		ps.ParentType = mValue.Type()
+		ps.Field = fValue
+		ps.FieldName = key
+		ps.Path = key
+		ps.Tag = tag
+		ps.validator = v
+
+		// the values usually come from a client, so a value of the wrong type is reported as an error of its key
+		// instead of panicing like a misused tag would
+		if err := executeRecovered(parsed, &ps); err != nil {
+			errs.addField(key, err)
+		}
+	}
+	if len(errs) > 0 {
+		v.finish(errs)
+		return errs
+	}
+	return nil
+}
+
+// finish sorts the errors returned by the validator and sets their formatter, as configured
+func (v *validator) finish(errs FieldErrors) {
+	if v.sortErrors {
+		errs.Sort()
+	}
+	if v.errorFormatter != nil {
+		for _, err := range errs {
+			if fe, ok := err.(*FieldError); ok {
+				fe.formatter = v.errorFormatter
+			}
+		}
+	}
+}
+
+// traversal is the state of a single call to traverse
+type traversal struct {
+	// tag is the language of the error messages
+	tag language.Tag
+
+	// isSyntaxCheck is true if the syntax of the validation tags is being checked instead of the values of the fields
+	isSyntaxCheck bool
+
+	// root is the value that was passed in to be validated
+	root reflect.Value
+
+	// isVerbose is true if the paths of the fields that were checked are recorded
+	isVerbose bool
+
+	// checked are the paths of every field whose validation tag was evaluated
+	checked []string
+
+	// mask are the dot separated names of the only fields that are validated, split by the dots. All fields are validated if it is nil
+	mask [][]string
+
+	// names are the json and go names of each of the struct fields the traversal is inside of
+	names [][2]string
+
+	// depth is how many values the traversal is nested inside of
+	depth int
+
+	// visiting are the values the traversal is inside of, so that values that contain themselves are only traversed once
+	visiting map[visit]bool
+
+	// emit is called with each error as it is found instead of the errors being returned, if it is set
+	emit func(path string, err error) bool
+
+	// isStopped is true if emit returned false, and the traversal should stop
+	isStopped bool
+}
+
+// add adds the errors of the field at the path passed in to errs, or emits them one at a time if the traversal has an emit func
+func (t *traversal) add(errs *FieldErrors, path string, err error) {
+	if t.emit == nil {
+		errs.addField(path, err)
+		return
+	}
+	var es FieldErrors
+	es.addField(path, err)
+	for _, e := range es {
+		if t.isStopped {
+			return
+		}
+		ePath := path
+		if fe, ok := e.(*FieldError); ok {
+			ePath = fe.Path
+		}
+		t.isStopped = !t.emit(ePath, e)
+	}
+}
+
+// visit is the address and type of a value being traversed. The type is needed because a struct and its first field share an address
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// visitOf returns the visit of a value that could contain itself, i.e. structs and arrays that were reached through a
+// pointer, slices and maps
+func visitOf(iValue reflect.Value) (visit, bool) {
+	switch iValue.Kind() {
+	case reflect.Struct, reflect.Array:
+		if iValue.CanAddr() {
+			return visit{iValue.UnsafeAddr(), iValue.Type()}, true
+		}
+	case reflect.Slice, reflect.Map:
+		if !iValue.IsNil() {
+			return visit{iValue.Pointer(), iValue.Type()}, true
+		}
+	}
+	return visit{}, false
+}
+
+// selects returns whether or not the field with the names passed in is validated, and whether or not any of the fields
+// nested inside of it are
+func (t *traversal) selects(names [][2]string) (isSelected bool, isNestedSelected bool) {
+	if t.mask == nil {
+		return true, true
+	}
+	for _, m := range t.mask {
+		isMatch := true
+		for i := 0; i < len(m) && i < len(names); i++ {
+			isMatch = isMatch && (m[i] == names[i][0] || m[i] == names[i][1])
+		}
+		if isMatch && len(m) <= len(names) {
+			return true, true
+		}
+		isNestedSelected = isNestedSelected || isMatch
+	}
+	return false, isNestedSelected
+}
+
 // traverse walks slices, arrays, and struct searching for validation tags
-func (v *validator) traverse(tag language.Tag, isSyntaxCheck bool, iRoot, iValue reflect.Value) FieldErrors {
+func (v *validator) traverse(t *traversal, iValue reflect.Value, path string) FieldErrors {
 	var errs FieldErrors
 	iType := iValue.Type()
 	iKind := iType.Kind()
 
-	// dereference pointers
-	if iKind == reflect.Ptr {
+	// dereference pointers and interfaces
+	for iKind == reflect.Ptr || iKind == reflect.Interface {
+		if iValue.IsNil() {
+			return nil
+		}
 		iValue = iValue.Elem()
 		iType = iValue.Type()
 		iKind = iType.Kind()
 	}
 
+	// stop at values that the traversal is already inside of, e.g. a node of a tree that points back to its parent
+	if key, ok := visitOf(iValue); ok {
+		if t.visiting[key] {
+			return nil
+		} else if t.visiting == nil {
+			t.visiting = make(map[visit]bool)
+		}
+		t.visiting[key] = true
+		defer delete(t.visiting, key)
+	}
+
+	// stop at values that are nested too deeply
+	if v.maxDepth > 0 && t.depth > v.maxDepth {
+		t.add(&errs, path, fmt.Errorf("'%s' is nested more than %d levels deep", path, v.maxDepth))
+		return errs
+	}
+	t.depth++
+	defer func() { t.depth-- }()
+
 	// traverse slices and arrays
 	if iKind == reflect.Slice || iKind == reflect.Array {
-		for i, l := 0, iValue.Len(); i < l; i++ {
-			if es := v.traverse(tag, isSyntaxCheck, iRoot, iValue.Index(i)); len(es) > 0 {
+		for i, l := 0, iValue.Len(); i < l && !t.isStopped; i++ {
+			if es := v.traverse(t, iValue.Index(i), fmt.Sprintf("%s[%d]", path, i)); len(es) > 0 {
+				errs.Add(es...)
+			}
+		}
+	}
+
+	// traverse the values of maps
+	if iKind == reflect.Map {
+		for iter := iValue.MapRange(); iter.Next() && !t.isStopped; {
+			if es := v.traverse(t, iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key())); len(es) > 0 {
 				errs.Add(es...)
 			}
 		}
@@ -146,11 +1000,21 @@ func (v *validator) traverse(tag language.Tag, isSyntaxCheck bool, iRoot, iValue
 
 	// traverse fields in a struct and validate
 	if iKind == reflect.Struct {
-		for i, l := 0, iType.NumField(); i < l; i++ {
-			field := iType.Field(i)
+		for i, f := range v.fields(iType) {
+			if t.isStopped {
+				break
+			}
+			fPath := f.name
+			if len(path) > 0 {
+				fPath = path + "." + f.name
+			}
 			fValue := iValue.Field(i)
+			if len(f.source) > 0 && f.err == nil {
+				fValue = source(iValue, f.source)
+			}
 			fType := fValue.Type()
 			fKind := fType.Kind()
+			declaredType := fType
 
 			// dereference pointers
 			if fKind == reflect.Ptr && !fValue.IsNil() {
@@ -159,51 +1023,101 @@ func (v *validator) traverse(tag language.Tag, isSyntaxCheck bool, iRoot, iValue
 				fKind = fType.Kind()
 			}
 
-			// validate a field with the validation tag
-			if validator, ok := field.Tag.Lookup(v.tag); ok {
-				fieldName, ok := field.Tag.Lookup("json")
-				if ok {
-					fieldName = strings.Split(fieldName, ",")[0]
-				} else {
-					fieldName = field.Name
+			// skip fields that are explicitly ignored
+			if f.isIgnored {
+				continue
+			}
+
+			// skip fields that aren't selected by the mask, unless fields nested inside of them are
+			names := append(t.names, [2]string{f.name, f.goName})
+			isSelected, isNestedSelected := t.selects(names)
+			if !isSelected && !isNestedSelected {
+				continue
+			}
+
+			// validate a field with the validation tag, unless only the fields nested inside of it are selected
+			if isSelected && f.err != nil {
+				t.add(&errs, fPath, f.err)
+			} else if isSelected && f.parsed != nil {
+				// nil pointers are passed to the rules as the zero value of the type they point to, so that they're
+				// treated the same as any other field that isn't set
+				rValue := fValue
+				if fKind == reflect.Ptr && fValue.IsNil() {
+					rValue = reflect.Zero(fType.Elem())
 				}
 
 				// create params
 				var ps RuleParams
-				ps.Root = iRoot
+				ps.Root = t.root
+				ps.RootType = t.root.Type()
 				ps.Parent = iValue
-				ps.Field = fValue
-				ps.FieldName = fieldName
-				ps.Tag = tag
-
-				// get the parse tree
-				if parsed, err := v.parser.parse(validator, v.rules); err != nil {
-					errs.Add(&FieldError{
-						Message: err,
-					})
-				} else if err := parsed.execute(&ps); err != nil {
-					if !isSyntaxCheck {
-						errs.Add(&FieldError{
-							Message: err,
-						})
+				ps.ParentType = iType
+				ps.Field = rValue
+				ps.FieldName = f.name
+				ps.Path = fPath
+				ps.Tag = t.tag
+				ps.validator = v
+
+				// execute the parse tree, unless the field is empty and can be skipped
+				if isSkipped := v.skipEmpty && !t.isSyntaxCheck && !f.isPresenceChecked && !ps.hasValue(rValue); isSkipped {
+					continue
+				} else if t.isVerbose {
+					t.checked = append(t.checked, fPath)
+				}
+				if t.isSyntaxCheck {
+					if err := checkSyntax(f.parsed, &ps); err != nil {
+						t.add(&errs, fPath, err)
+					}
+				} else if err := f.parsed.execute(&ps); err != nil {
+					if v.typeNames {
+						err = withTypeName(err, f.name, declaredType)
 					}
+					t.add(&errs, fPath, err)
 				}
-
 			}
 
 			// traverse the field if possible
-			if fKind == reflect.Struct || fKind == reflect.Array || fKind == reflect.Slice {
-				if es := v.traverse(tag, isSyntaxCheck, iRoot, fValue); len(es) > 0 {
+			isNested := fKind == reflect.Struct || fKind == reflect.Array || fKind == reflect.Slice || fKind == reflect.Map
+			if isInterface := fKind == reflect.Interface && !fValue.IsNil(); !f.isNoDive && (isNested || isInterface) {
+				parent := t.names
+				t.names = names
+				if es := v.traverse(t, fValue, fPath); len(es) > 0 {
 					errs.Add(es...)
 				}
+				t.names = parent
 			}
 		}
 	}
 	return errs
 }
 
+// checkSyntax executes the parse tree of a field and returns the panic of any rule that was misused as an error
+func checkSyntax(n *node, ps *RuleParams) (err error) {
+	defer recoverRule(&err)
+	n.execute(ps)
+	return nil
+}
+
+// executeRecovered executes the parse tree of a field and returns its error, or the panic of any rule that was misused as an error
+func executeRecovered(n *node, ps *RuleParams) (err error) {
+	defer recoverRule(&err)
+	return n.execute(ps)
+}
+
+// recoverRule sets err to the panic of a rule that was misused, if there is one. It must be deferred
+func recoverRule(err *error) {
+	if r := recover(); r != nil {
+		if e, ok := r.(error); ok {
+			*err = e
+		} else {
+			*err = fmt.Errorf("%+v", r)
+		}
+	}
+}
+
 func (v *validator) CheckSyntax(i interface{}) error {
-	out := make(chan error)
+	// out is buffered so that rules that finish after the timeout don't leak the goroutine
+	out := make(chan error, 1)
 	go func() {
 		defer close(out)
 		defer func() {
@@ -216,9 +1130,14 @@ func (v *validator) CheckSyntax(i interface{}) error {
 			}
 		}()
 		iValue := reflect.ValueOf(i)
-		if err := v.traverse(language.English, true, iValue, iValue); err != nil {
+		if err := v.traverse(&traversal{tag: language.English, isSyntaxCheck: true, root: iValue}, iValue, ""); err != nil {
 			out <- err
 		}
 	}()
-	return <-out
+	select {
+	case err := <-out:
+		return err
+	case <-time.After(v.syntaxCheckTimeout):
+		return fmt.Errorf("the syntax check timed out after %s", v.syntaxCheckTimeout)
+	}
 }